@@ -0,0 +1,56 @@
+package address
+
+import (
+	"github.com/mhrynenko/cryptography_course/ecdsa"
+	"github.com/mhrynenko/cryptography_course/ripemd160"
+	"github.com/mhrynenko/cryptography_course/secp256k1"
+	"github.com/mhrynenko/cryptography_course/sha256"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidAddressLength = errors.New("address has invalid length")
+	ErrInvalidAddressFormat = errors.New("address has invalid format")
+	ErrInvalidChecksum      = errors.New("address checksum is invalid")
+	ErrWrongNetwork         = errors.New("address belongs to another network")
+)
+
+// curve both Ethereum and Bitcoin keys are secp256k1 points
+var curve = secp256k1.S256()
+
+// Network Bitcoin address prefixes, they make addresses of different networks incompatible
+type Network struct {
+	// PubKeyHashVersion version byte of Base58Check P2PKH address
+	PubKeyHashVersion byte
+	// Bech32HRP human-readable part of segwit address
+	Bech32HRP string
+}
+
+var (
+	MainNet = Network{PubKeyHashVersion: 0x00, Bech32HRP: "bc"}
+	TestNet = Network{PubKeyHashVersion: 0x6f, Bech32HRP: "tb"}
+)
+
+// PublicKeyHash RIPEMD160(SHA256(compressed public key)), both P2PKH and P2WPKH addresses encode it
+func PublicKeyHash(pk ecdsa.PublicKey) ([]byte, error) {
+	if err := validatePublicKey(pk); err != nil {
+		return nil, err
+	}
+
+	sha := sha256.Compute(pk.MarshalCompressed(curve))
+	result := ripemd160.Compute(sha[:])
+
+	return result[:], nil
+}
+
+func validatePublicKey(pk ecdsa.PublicKey) error {
+	if pk.X == nil || pk.Y == nil {
+		return ecdsa.ErrNilPublicKey
+	}
+
+	if !curve.IsOnCurve(pk.X, pk.Y) {
+		return ecdsa.ErrPublicKeyIsNotOnCurve
+	}
+
+	return nil
+}
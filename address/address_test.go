@@ -0,0 +1,325 @@
+package address
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mhrynenko/cryptography_course/ecdsa"
+	"github.com/pkg/errors"
+)
+
+type KeyVector struct {
+	d        int64
+	ethereum string
+	p2pkh    string
+	p2wpkh   string
+	network  Network
+}
+
+var keyVectors = []KeyVector{
+	{
+		d:        1,
+		ethereum: "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf",
+		p2pkh:    "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		p2wpkh:   "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		network:  MainNet,
+	},
+	{
+		d:        1,
+		ethereum: "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf",
+		p2pkh:    "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r",
+		p2wpkh:   "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx",
+		network:  TestNet,
+	},
+	{
+		d:        2,
+		ethereum: "0x2B5AD5c4795c026514f8317c7a215E218DcCD6cF",
+	},
+	{
+		d:        3,
+		ethereum: "0x6813Eb9362372EEF6200f3b1dbC3f819671cBA69",
+	},
+}
+
+func TestKeyVectors(t *testing.T) {
+	for i, vector := range keyVectors {
+		key, err := ecdsa.NewPrivateKey(curve, big.NewInt(vector.d))
+		if err != nil {
+			t.Fatalf("ecdsa.NewPrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		ethereum, err := EthereumAddress(key.PK)
+		if err != nil {
+			t.Fatalf("address.EthereumAddress: unexcpected error `%s`", err.Error())
+		}
+
+		if ethereum != vector.ethereum {
+			t.Errorf("address.EthereumAddress: wrong address for %d vector", i)
+		}
+
+		if vector.p2pkh == "" {
+			continue
+		}
+
+		hash, _ := PublicKeyHash(key.PK)
+
+		p2pkh, err := P2PKHAddress(key.PK, vector.network)
+		if err != nil {
+			t.Fatalf("address.P2PKHAddress: unexcpected error `%s`", err.Error())
+		}
+
+		if p2pkh != vector.p2pkh {
+			t.Errorf("address.P2PKHAddress: wrong address for %d vector", i)
+		}
+
+		parsed, err := ParseP2PKHAddress(p2pkh, vector.network)
+		if err != nil || common.Bytes2Hex(parsed) != common.Bytes2Hex(hash) {
+			t.Errorf("address.ParseP2PKHAddress: wrong hash for %d vector", i)
+		}
+
+		p2wpkh, err := P2WPKHAddress(key.PK, vector.network)
+		if err != nil {
+			t.Fatalf("address.P2WPKHAddress: unexcpected error `%s`", err.Error())
+		}
+
+		if p2wpkh != vector.p2wpkh {
+			t.Errorf("address.P2WPKHAddress: wrong address for %d vector", i)
+		}
+
+		parsed, err = ParseP2WPKHAddress(strings.ToUpper(p2wpkh), vector.network)
+		if err != nil || common.Bytes2Hex(parsed) != common.Bytes2Hex(hash) {
+			t.Errorf("address.ParseP2WPKHAddress: wrong hash for %d vector", i)
+		}
+	}
+}
+
+func TestEthereumAddressGeth(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		key, err := ecdsa.GeneratePrivateKey(curve)
+		if err != nil {
+			t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		address, err := EthereumAddress(key.PK)
+		if err != nil {
+			t.Fatalf("address.EthereumAddress: unexcpected error `%s`", err.Error())
+		}
+
+		lib := crypto.PubkeyToAddress(stdecdsa.PublicKey{Curve: crypto.S256(), X: key.PK.X, Y: key.PK.Y})
+		if address != lib.Hex() {
+			t.Errorf("address.EthereumAddress: address differs from go-ethereum `%s` != `%s`", address, lib.Hex())
+		}
+
+		parsed, err := ParseEthereumAddress(address)
+		if err != nil || common.Bytes2Hex(parsed) != common.Bytes2Hex(lib.Bytes()) {
+			t.Errorf("address.ParseEthereumAddress: wrong address for %d key", i)
+		}
+	}
+}
+
+// TestEIP55 vectors from EIP-55
+func TestEIP55(t *testing.T) {
+	checksummed := []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+		"0xde709f2102306220921060314715629080e2fb77",
+		"0x27b1fdb04752bbc536007a920d24acb045561c26",
+	}
+
+	for i, address := range checksummed {
+		raw := common.FromHex(address)
+
+		if ChecksumAddress(raw) != address {
+			t.Errorf("address.ChecksumAddress: wrong checksum for %d address", i)
+		}
+
+		if ChecksumAddress(raw) != common.HexToAddress(address).Hex() {
+			t.Errorf("address.ChecksumAddress: checksum differs from go-ethereum for %d address", i)
+		}
+
+		if !IsValidEthereumAddress(address) {
+			t.Errorf("address.IsValidEthereumAddress: valid %d address is rejected", i)
+		}
+
+		if !IsValidEthereumAddress("0x" + strings.ToLower(address[2:])) {
+			t.Errorf("address.IsValidEthereumAddress: lowercase %d address is rejected", i)
+		}
+	}
+
+	invalid := []struct {
+		address string
+		err     error
+	}{
+		{address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", err: ErrInvalidChecksum},
+		{address: "0x5AaEB6053f3e94c9B9a09F33669435e7eF1bEaED", err: ErrInvalidChecksum},
+		{address: "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", err: ErrInvalidAddressFormat},
+		{address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe", err: ErrInvalidAddressLength},
+		{address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAedAA", err: ErrInvalidAddressLength},
+		{address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", err: ErrInvalidAddressFormat},
+	}
+
+	for _, c := range invalid {
+		if _, err := ParseEthereumAddress(c.address); err != c.err {
+			t.Errorf("address.ParseEthereumAddress: wrong error for `%s`, err = %v", c.address, err)
+		}
+	}
+}
+
+// TestBech32 checksum vectors from BIP-173 and BIP-350
+func TestBech32(t *testing.T) {
+	valid := []struct {
+		s       string
+		variant bech32Variant
+	}{
+		{s: "A12UEL5L", variant: bech32},
+		{s: "a12uel5l", variant: bech32},
+		{s: "an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs", variant: bech32},
+		{s: "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", variant: bech32},
+		{s: "split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w", variant: bech32},
+		{s: "?1ezyfcl", variant: bech32},
+		{s: "A1LQFN3A", variant: bech32m},
+		{s: "a1lqfn3a", variant: bech32m},
+		{s: "an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6", variant: bech32m},
+		{s: "abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx", variant: bech32m},
+		{s: "split1checkupstagehandshakeupstreamerranterredcaperredlc445v", variant: bech32m},
+		{s: "?1v759aa", variant: bech32m},
+	}
+
+	for i, vector := range valid {
+		hrp, data, variant, err := bech32Decode(vector.s)
+		if err != nil {
+			t.Errorf("address.bech32Decode: unexcpected error `%s` for %d vector", err.Error(), i)
+			continue
+		}
+
+		if variant != vector.variant {
+			t.Errorf("address.bech32Decode: wrong variant for %d vector", i)
+		}
+
+		if bech32Encode(hrp, data, variant) != strings.ToLower(vector.s) {
+			t.Errorf("address.bech32Encode: wrong encoding for %d vector", i)
+		}
+	}
+
+	invalid := []string{
+		" 1nwldj5",
+		"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx",
+		"pzry9x0s0muk",
+		"1pzry9x0s0muk",
+		"x1b4n0q5v",
+		"li1dgmt3",
+		"A1G7SGD8",
+		"10a06t8",
+		"1qzzfhee",
+		"a12UEL5L",
+	}
+
+	for _, s := range invalid {
+		if _, _, _, err := bech32Decode(s); err == nil {
+			t.Errorf("address.bech32Decode: invalid string `%s` is accepted", s)
+		}
+	}
+}
+
+// TestSegwitAddress vectors from BIP-173 and BIP-350, script is witness version opcode, program length and program
+func TestSegwitAddress(t *testing.T) {
+	valid := []struct {
+		hrp     string
+		address string
+		script  string
+	}{
+		{hrp: "bc", address: "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", script: "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{hrp: "tb", address: "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", script: "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		{hrp: "bc", address: "bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y", script: "5128751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{hrp: "bc", address: "BC1SW50QGDZ25J", script: "6002751e"},
+		{hrp: "tb", address: "tb1qqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesrxh6hy", script: "0020000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+		{hrp: "tb", address: "tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c", script: "5120000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+		{hrp: "bc", address: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", script: "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+	}
+
+	for i, vector := range valid {
+		version, program, err := DecodeSegwitAddress(vector.hrp, vector.address)
+		if err != nil {
+			t.Errorf("address.DecodeSegwitAddress: unexcpected error `%s` for %d vector", err.Error(), i)
+			continue
+		}
+
+		opcode := version
+		if version != 0 {
+			opcode += 0x50
+		}
+
+		script := append([]byte{opcode, byte(len(program))}, program...)
+		if common.Bytes2Hex(script) != vector.script {
+			t.Errorf("address.DecodeSegwitAddress: wrong program for %d vector", i)
+		}
+
+		encoded, err := EncodeSegwitAddress(vector.hrp, version, program)
+		if err != nil || encoded != strings.ToLower(vector.address) {
+			t.Errorf("address.EncodeSegwitAddress: wrong address for %d vector", i)
+		}
+	}
+
+	invalid := []struct {
+		hrp     string
+		address string
+		err     error
+	}{
+		{hrp: "tb", address: "tc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq5zuyut", err: ErrWrongNetwork},
+		{hrp: "bc", address: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", err: ErrInvalidChecksumVariant},
+		{hrp: "tb", address: "tb1z0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqglt7rf", err: ErrInvalidChecksumVariant},
+		{hrp: "bc", address: "BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL", err: ErrInvalidChecksumVariant},
+		{hrp: "bc", address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh", err: ErrInvalidChecksumVariant},
+		{hrp: "tb", address: "tb1q0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq24jc47", err: ErrInvalidChecksumVariant},
+		{hrp: "bc", address: "bc1p38j9r5y49hruaue7wxjce0updqjuyyx0kh56v8s25huc6995vvpql3jow4", err: ErrInvalidBech32},
+		{hrp: "bc", address: "BC130XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ7ZWS8R", err: ErrInvalidWitnessVersion},
+		{hrp: "bc", address: "bc1pw5dgrnzv", err: ErrInvalidWitnessProgram},
+		{hrp: "bc", address: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v8n0nx0muaewav253zgeav", err: ErrInvalidWitnessProgram},
+		{hrp: "bc", address: "BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P", err: ErrInvalidWitnessProgram},
+		{hrp: "tb", address: "tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq47Zagq", err: ErrInvalidBech32},
+		{hrp: "bc", address: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v07qwwzcrf", err: ErrInvalidWitnessProgram},
+		{hrp: "tb", address: "tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vpggkg4j", err: ErrInvalidWitnessProgram},
+		{hrp: "bc", address: "bc1gmk9yu", err: ErrInvalidWitnessVersion},
+	}
+
+	for _, c := range invalid {
+		if _, _, err := DecodeSegwitAddress(c.hrp, c.address); errors.Cause(err) != c.err {
+			t.Errorf("address.DecodeSegwitAddress: wrong error for `%s`, err = %v", c.address, err)
+		}
+	}
+}
+
+func TestInvalidBitcoinAddress(t *testing.T) {
+	p2wshAddress := "bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3"
+	taprootAddress := "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"
+
+	for _, address := range []string{p2wshAddress, taprootAddress} {
+		if _, err := ParseP2WPKHAddress(address, MainNet); errors.Cause(err) != ErrInvalidWitnessProgram {
+			t.Errorf("address.ParseP2WPKHAddress: wrong error for `%s`, err = %v", address, err)
+		}
+	}
+
+	if _, err := ParseP2WPKHAddress("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", TestNet); err != ErrWrongNetwork {
+		t.Errorf("address.ParseP2WPKHAddress: mainnet address is accepted as testnet one")
+	}
+
+	if _, err := ParseP2PKHAddress("1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", TestNet); err != ErrWrongNetwork {
+		t.Errorf("address.ParseP2PKHAddress: mainnet address is accepted as testnet one")
+	}
+
+	if _, err := ParseP2PKHAddress("1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMh", MainNet); err == nil {
+		t.Errorf("address.ParseP2PKHAddress: address with wrong checksum is accepted")
+	}
+
+	if _, err := P2PKHAddress(ecdsa.PublicKey{X: big.NewInt(1), Y: big.NewInt(1)}, MainNet); err != ecdsa.ErrPublicKeyIsNotOnCurve {
+		t.Errorf("address.P2PKHAddress: point which is not on curve is accepted")
+	}
+}
@@ -0,0 +1,224 @@
+package address
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidBech32          = errors.New("invalid bech32 string")
+	ErrInvalidWitnessVersion  = errors.New("invalid witness version")
+	ErrInvalidWitnessProgram  = errors.New("invalid witness program")
+	ErrInvalidChecksumVariant = errors.New("checksum variant does not match witness version")
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32Variant constant checksum is XORed with, BIP-173 Bech32 is used by witness version 0,
+// BIP-350 Bech32m by versions 1-16
+type bech32Variant uint32
+
+const (
+	bech32  bech32Variant = 1
+	bech32m bech32Variant = 0x2bc830a3
+)
+
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// bech32Polymod BCH code checksum over GF(32)
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+// bech32HRPExpand high bits of every hrp character, zero, then low bits of every character
+func bech32HRPExpand(hrp string) []byte {
+	result := make([]byte, 0, 2*len(hrp)+1)
+
+	for i := 0; i < len(hrp); i++ {
+		result = append(result, hrp[i]>>5)
+	}
+
+	result = append(result, 0)
+
+	for i := 0; i < len(hrp); i++ {
+		result = append(result, hrp[i]&31)
+	}
+
+	return result
+}
+
+// bech32Encode data is a slice of 5-bit values, hrp must be lowercase
+func bech32Encode(hrp string, data []byte, variant bech32Variant) string {
+	values := append(bech32HRPExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ uint32(variant)
+
+	var result strings.Builder
+	result.WriteString(hrp)
+	result.WriteByte('1')
+
+	for _, v := range data {
+		result.WriteByte(bech32Charset[v])
+	}
+
+	for i := 0; i < 6; i++ {
+		result.WriteByte(bech32Charset[(polymod>>(5*(5-i)))&31])
+	}
+
+	return result.String()
+}
+
+// bech32Decode returns lowercase hrp and 5-bit data without checksum
+func bech32Decode(s string) (string, []byte, bech32Variant, error) {
+	if len(s) > 90 {
+		return "", nil, 0, errors.Wrap(ErrInvalidBech32, "string is too long")
+	}
+
+	if s != strings.ToLower(s) && s != strings.ToUpper(s) {
+		return "", nil, 0, errors.Wrap(ErrInvalidBech32, "mixed case")
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] < 33 || s[i] > 126 {
+			return "", nil, 0, errors.Wrap(ErrInvalidBech32, "invalid character")
+		}
+	}
+
+	s = strings.ToLower(s)
+
+	//separator is the last '1', checksum takes 6 characters
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, 0, errors.Wrap(ErrInvalidBech32, "invalid separator position")
+	}
+
+	hrp := s[:pos]
+
+	data := make([]byte, 0, len(s)-pos-1)
+	for i := pos + 1; i < len(s); i++ {
+		v := strings.IndexByte(bech32Charset, s[i])
+		if v < 0 {
+			return "", nil, 0, errors.Wrap(ErrInvalidBech32, "invalid data character")
+		}
+
+		data = append(data, byte(v))
+	}
+
+	variant := bech32Variant(bech32Polymod(append(bech32HRPExpand(hrp), data...)))
+	if variant != bech32 && variant != bech32m {
+		return "", nil, 0, ErrInvalidChecksum
+	}
+
+	return hrp, data[:len(data)-6], variant, nil
+}
+
+// convertBits regroups bits, e.g. 8-bit bytes to 5-bit values, pad adds zero bits to the last group,
+// without it incomplete group must be zero padding shorter than from bits
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	var acc, bits uint
+	maxValue := uint(1)<<to - 1
+
+	result := make([]byte, 0, len(data)*int(from)/int(to)+1)
+
+	for _, v := range data {
+		if uint(v)>>from != 0 {
+			return nil, ErrInvalidWitnessProgram
+		}
+
+		acc = acc<<from | uint(v)
+		bits += from
+
+		for bits >= to {
+			bits -= to
+			result = append(result, byte((acc>>bits)&maxValue))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			result = append(result, byte((acc<<(to-bits))&maxValue))
+		}
+	} else if bits >= from || (acc<<(to-bits))&maxValue != 0 {
+		return nil, ErrInvalidWitnessProgram
+	}
+
+	return result, nil
+}
+
+// EncodeSegwitAddress BIP-173 and BIP-350 segwit address: hrp, witness version and witness program
+func EncodeSegwitAddress(hrp string, version byte, program []byte) (string, error) {
+	if err := validateWitness(version, program); err != nil {
+		return "", err
+	}
+
+	data, err := convertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	variant := bech32m
+	if version == 0 {
+		variant = bech32
+	}
+
+	return bech32Encode(strings.ToLower(hrp), append([]byte{version}, data...), variant), nil
+}
+
+// DecodeSegwitAddress returns witness version and program, address hrp must be equal to hrp
+func DecodeSegwitAddress(hrp, address string) (byte, []byte, error) {
+	decodedHRP, data, variant, err := bech32Decode(address)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if decodedHRP != strings.ToLower(hrp) {
+		return 0, nil, ErrWrongNetwork
+	}
+
+	if len(data) == 0 {
+		return 0, nil, ErrInvalidWitnessVersion
+	}
+
+	version := data[0]
+
+	program, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if err = validateWitness(version, program); err != nil {
+		return 0, nil, err
+	}
+
+	if (version == 0) != (variant == bech32) {
+		return 0, nil, ErrInvalidChecksumVariant
+	}
+
+	return version, program, nil
+}
+
+// validateWitness version is 0-16, program is 2-40 bytes, version 0 program is 20 (P2WPKH) or 32 (P2WSH) bytes
+func validateWitness(version byte, program []byte) error {
+	if version > 16 {
+		return ErrInvalidWitnessVersion
+	}
+
+	if len(program) < 2 || len(program) > 40 || (version == 0 && len(program) != 20 && len(program) != 32) {
+		return ErrInvalidWitnessProgram
+	}
+
+	return nil
+}
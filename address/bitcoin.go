@@ -0,0 +1,58 @@
+package address
+
+import (
+	"github.com/mhrynenko/cryptography_course/base58"
+	"github.com/mhrynenko/cryptography_course/ecdsa"
+)
+
+// P2PKHAddress Base58Check(version || PublicKeyHash), compressed public key is used
+func P2PKHAddress(pk ecdsa.PublicKey, network Network) (string, error) {
+	hash, err := PublicKeyHash(pk)
+	if err != nil {
+		return "", err
+	}
+
+	return base58.CheckEncode(append([]byte{network.PubKeyHashVersion}, hash...)), nil
+}
+
+// ParseP2PKHAddress verifies checksum and version byte and returns public key hash
+func ParseP2PKHAddress(address string, network Network) ([]byte, error) {
+	data, err := base58.CheckDecode(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) != 21 {
+		return nil, ErrInvalidAddressLength
+	}
+
+	if data[0] != network.PubKeyHashVersion {
+		return nil, ErrWrongNetwork
+	}
+
+	return data[1:], nil
+}
+
+// P2WPKHAddress segwit version 0 address, witness program is PublicKeyHash
+func P2WPKHAddress(pk ecdsa.PublicKey, network Network) (string, error) {
+	hash, err := PublicKeyHash(pk)
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeSegwitAddress(network.Bech32HRP, 0, hash)
+}
+
+// ParseP2WPKHAddress returns public key hash, other segwit addresses (e.g. P2WSH or taproot) are rejected
+func ParseP2WPKHAddress(address string, network Network) ([]byte, error) {
+	version, program, err := DecodeSegwitAddress(network.Bech32HRP, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if version != 0 || len(program) != 20 {
+		return nil, ErrInvalidWitnessProgram
+	}
+
+	return program, nil
+}
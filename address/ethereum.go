@@ -0,0 +1,73 @@
+package address
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/mhrynenko/cryptography_course/ecdsa"
+	"github.com/mhrynenko/cryptography_course/sha3"
+)
+
+// EthereumAddressLength address is the last 20 bytes of Keccak-256 hash
+const EthereumAddressLength = 20
+
+// EthereumAddress Keccak256(X || Y)[12:], where X || Y is uncompressed public key without 0x04 prefix,
+// the result is EIP-55 checksummed
+func EthereumAddress(pk ecdsa.PublicKey) (string, error) {
+	if err := validatePublicKey(pk); err != nil {
+		return "", err
+	}
+
+	hash := sha3.ComputeKeccak256(pk.Marshal(curve)[1:])
+
+	return ChecksumAddress(hash[32-EthereumAddressLength:]), nil
+}
+
+// ChecksumAddress EIP-55 encoding: hex letter is uppercase when the matching nibble of
+// Keccak256(lowercase hex address) is >= 8
+func ChecksumAddress(address []byte) string {
+	result := []byte(hex.EncodeToString(address))
+	hash := sha3.ComputeKeccak256(result)
+
+	for i, c := range result {
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+
+		if c >= 'a' && nibble >= 8 {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+
+	return "0x" + string(result)
+}
+
+// ParseEthereumAddress decodes 0x prefixed hex address, all lowercase and all uppercase addresses have no checksum,
+// mixed case address must have valid EIP-55 checksum
+func ParseEthereumAddress(address string) ([]byte, error) {
+	if !strings.HasPrefix(address, "0x") {
+		return nil, ErrInvalidAddressFormat
+	}
+
+	encoded := address[2:]
+	if len(encoded) != 2*EthereumAddressLength {
+		return nil, ErrInvalidAddressLength
+	}
+
+	result, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidAddressFormat
+	}
+
+	if encoded != strings.ToLower(encoded) && encoded != strings.ToUpper(encoded) && ChecksumAddress(result) != address {
+		return nil, ErrInvalidChecksum
+	}
+
+	return result, nil
+}
+
+func IsValidEthereumAddress(address string) bool {
+	_, err := ParseEthereumAddress(address)
+	return err == nil
+}
@@ -0,0 +1,117 @@
+package base58
+
+import (
+	"math/big"
+
+	"github.com/mhrynenko/cryptography_course/sha256"
+	"github.com/pkg/errors"
+)
+
+// alphabet without 0, O, I and l, so similar looking symbols can not be confused
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+const checksumSize = 4
+
+var (
+	ErrInvalidCharacter = errors.New("invalid base58 character")
+	ErrInvalidChecksum  = errors.New("checksum is invalid")
+	ErrTooShort         = errors.New("data is too short to contain checksum")
+)
+
+var radix = big.NewInt(58)
+
+var decodeMap = func() [256]int {
+	var result [256]int
+	for i := range result {
+		result[i] = -1
+	}
+
+	for i, c := range alphabet {
+		result[c] = i
+	}
+
+	return result
+}()
+
+// Encode data as big endian number in base 58, every leading zero byte is encoded as '1'
+func Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	num := new(big.Int).SetBytes(data)
+	mod := new(big.Int)
+
+	var result []byte
+	for num.Sign() > 0 {
+		num.DivMod(num, radix, mod)
+		result = append(result, alphabet[mod.Int64()])
+	}
+
+	for i := 0; i < zeros; i++ {
+		result = append(result, alphabet[0])
+	}
+
+	// digits were collected from the least significant one
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return string(result)
+}
+
+func Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	num := new(big.Int)
+	for i := 0; i < len(s); i++ {
+		digit := decodeMap[s[i]]
+		if digit < 0 {
+			return nil, ErrInvalidCharacter
+		}
+
+		num.Mul(num, radix)
+		num.Add(num, big.NewInt(int64(digit)))
+	}
+
+	return append(make([]byte, zeros), num.Bytes()...), nil
+}
+
+// CheckEncode appends the first 4 bytes of SHA256(SHA256(data)) and encodes result
+func CheckEncode(data []byte) string {
+	return Encode(append(append([]byte(nil), data...), checksum(data)...))
+}
+
+// CheckDecode decodes s and verifies its checksum, data without checksum is returned
+func CheckDecode(s string) ([]byte, error) {
+	decoded, err := Decode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decoded) < checksumSize {
+		return nil, ErrTooShort
+	}
+
+	data, sum := decoded[:len(decoded)-checksumSize], decoded[len(decoded)-checksumSize:]
+
+	expected := checksum(data)
+	for i := range sum {
+		if sum[i] != expected[i] {
+			return nil, ErrInvalidChecksum
+		}
+	}
+
+	return data, nil
+}
+
+func checksum(data []byte) []byte {
+	first := sha256.Compute(data)
+	second := sha256.Compute(first[:])
+
+	return second[:checksumSize]
+}
@@ -0,0 +1,79 @@
+package base58
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Vector struct {
+	data    string
+	encoded string
+}
+
+// vectors from Bitcoin Core base58_encode_decode.json
+var vectors = []Vector{
+	{data: "", encoded: ""},
+	{data: "61", encoded: "2g"},
+	{data: "626262", encoded: "a3gV"},
+	{data: "636363", encoded: "aPEr"},
+	{data: "73696d706c792061206c6f6e6720737472696e67", encoded: "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+	{data: "00eb15231dfceb60925886b67d065299925915aeb172c06647", encoded: "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+	{data: "516b6fcd0f", encoded: "ABnLTmg"},
+	{data: "bf4f89001e670274dd", encoded: "3SEo3LWLoPntC"},
+	{data: "572e4794", encoded: "3EFU7m"},
+	{data: "ecac89cad93923c02321", encoded: "EJDM8drfXA6uyA"},
+	{data: "10c8511e", encoded: "Rt5zm"},
+	{data: "00000000000000000000", encoded: "1111111111"},
+}
+
+func TestVectors(t *testing.T) {
+	for i, vector := range vectors {
+		data := common.Hex2Bytes(vector.data)
+
+		if encoded := Encode(data); encoded != vector.encoded {
+			t.Errorf("base58.Encode: wrong result for %d vector, got %s", i, encoded)
+		}
+
+		decoded, err := Decode(vector.encoded)
+		if err != nil {
+			t.Fatalf("base58.Decode: unexcpected error `%s`", err.Error())
+		}
+
+		if common.Bytes2Hex(decoded) != vector.data {
+			t.Errorf("base58.Decode: wrong result for %d vector", i)
+		}
+	}
+
+	for _, invalid := range []string{"0", "O", "I", "l", "3mJr0", "3mJr O"} {
+		if _, err := Decode(invalid); err != ErrInvalidCharacter {
+			t.Errorf("base58.Decode: expected error for `%s`", invalid)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	// version byte 0 and hash160 of public key is Bitcoin P2PKH address
+	address := CheckEncode(common.Hex2Bytes("00f54a5851e9372b87810a8e60cdd2e7cfd80b6e31"))
+	if address != "1PMycacnJaSqwwJqjawXBErnLsZ7RkXUAs" {
+		t.Errorf("base58.CheckEncode: wrong result %s", address)
+	}
+
+	data, err := CheckDecode(address)
+	if err != nil {
+		t.Fatalf("base58.CheckDecode: unexcpected error `%s`", err.Error())
+	}
+
+	if common.Bytes2Hex(data) != "00f54a5851e9372b87810a8e60cdd2e7cfd80b6e31" {
+		t.Errorf("base58.CheckDecode: wrong result")
+	}
+
+	// the last symbol is changed
+	if _, err := CheckDecode("1PMycacnJaSqwwJqjawXBErnLsZ7RkXUAt"); err != ErrInvalidChecksum {
+		t.Errorf("base58.CheckDecode: expected checksum error")
+	}
+
+	if _, err := CheckDecode("11"); err != ErrTooShort {
+		t.Errorf("base58.CheckDecode: expected error for short data")
+	}
+}
@@ -0,0 +1,309 @@
+package bip32
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/mhrynenko/cryptography_course/base58"
+	"github.com/mhrynenko/cryptography_course/ecdsa"
+	"github.com/mhrynenko/cryptography_course/hmac"
+	"github.com/mhrynenko/cryptography_course/ripemd160"
+	"github.com/mhrynenko/cryptography_course/secp256k1"
+	"github.com/mhrynenko/cryptography_course/sha256"
+	"github.com/mhrynenko/cryptography_course/sha512"
+	"github.com/pkg/errors"
+)
+
+const (
+	// HardenedOffset child numbers starting from 2^31 are hardened, they can be derived only from private key
+	HardenedOffset uint32 = 0x80000000
+
+	SeedMinSize = 16
+	SeedMaxSize = 64
+
+	// serializedSize version(4) || depth(1) || parent fingerprint(4) || child number(4) || chain code(32) || key(33)
+	serializedSize = 78
+)
+
+var (
+	versionPrivate = []byte{0x04, 0x88, 0xad, 0xe4} // xprv
+	versionPublic  = []byte{0x04, 0x88, 0xb2, 0x1e} // xpub
+
+	masterHMACKey = []byte("Bitcoin seed")
+)
+
+var (
+	ErrInvalidSeedLength  = errors.New("seed must be from 16 to 64 bytes")
+	ErrInvalidChild       = errors.New("derived key is invalid, next index should be used")
+	ErrHardenedFromPublic = errors.New("hardened child can not be derived from public key")
+	ErrDepthTooBig        = errors.New("maximum depth is reached")
+	ErrInvalidPath        = errors.New("derivation path is invalid")
+	ErrInvalidKeyLength   = errors.New("serialized key length is invalid")
+	ErrUnknownVersion     = errors.New("unknown extended key version")
+	ErrInvalidMasterKey   = errors.New("master key must have zero parent fingerprint and child number")
+	ErrInvalidPrivateKey  = errors.New("private key is invalid")
+	ErrInvalidPublicKey   = errors.New("public key is invalid")
+)
+
+var curve = secp256k1.S256()
+
+// ExtendedKey key with chain code, PrivateKey is nil for extended public key
+type ExtendedKey struct {
+	PrivateKey        *ecdsa.PrivateKey
+	PublicKey         ecdsa.PublicKey
+	ChainCode         []byte
+	Depth             byte
+	ParentFingerprint []byte
+	ChildNumber       uint32
+}
+
+// NewMasterKey I = HMAC-SHA512("Bitcoin seed", seed), IL is master private key, IR is master chain code
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < SeedMinSize || len(seed) > SeedMaxSize {
+		return nil, ErrInvalidSeedLength
+	}
+
+	I := hmac.Compute(sha512.New, masterHMACKey, seed)
+
+	key, err := ecdsa.NewPrivateKey(curve, new(big.Int).SetBytes(I[:32]))
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidChild, err.Error())
+	}
+
+	return &ExtendedKey{
+		PrivateKey:        key,
+		PublicKey:         key.PK,
+		ChainCode:         I[32:],
+		ParentFingerprint: make([]byte, 4),
+	}, nil
+}
+
+func (k *ExtendedKey) IsPrivate() bool {
+	return k.PrivateKey != nil
+}
+
+// Neuter returns extended public key with the same chain code
+func (k *ExtendedKey) Neuter() *ExtendedKey {
+	return &ExtendedKey{
+		PublicKey:         k.PublicKey,
+		ChainCode:         k.ChainCode,
+		Depth:             k.Depth,
+		ParentFingerprint: k.ParentFingerprint,
+		ChildNumber:       k.ChildNumber,
+	}
+}
+
+// Fingerprint the first 4 bytes of RIPEMD160(SHA256(compressed public key))
+func (k *ExtendedKey) Fingerprint() []byte {
+	return hash160(k.PublicKey.MarshalCompressed(curve))[:4]
+}
+
+// Child derives child key with number index (CKDpriv or CKDpub), index >= HardenedOffset means hardened child
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if k.Depth == 255 {
+		return nil, ErrDepthTooBig
+	}
+
+	isHardened := index >= HardenedOffset
+	if isHardened && !k.IsPrivate() {
+		return nil, ErrHardenedFromPublic
+	}
+
+	//hardened: 0x00 || ser256(k) || ser32(i), normal: serP(K) || ser32(i)
+	var data []byte
+	if isHardened {
+		data = append([]byte{0}, k.PrivateKey.D.FillBytes(make([]byte, 32))...)
+	} else {
+		data = k.PublicKey.MarshalCompressed(curve)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	//I = HMAC-SHA512(c, data)
+	I := hmac.Compute(sha512.New, k.ChainCode, data)
+
+	IL := new(big.Int).SetBytes(I[:32])
+	if IL.Cmp(curve.Params().N) >= 0 {
+		return nil, ErrInvalidChild
+	}
+
+	child := &ExtendedKey{
+		ChainCode:         I[32:],
+		Depth:             k.Depth + 1,
+		ParentFingerprint: k.Fingerprint(),
+		ChildNumber:       index,
+	}
+
+	if k.IsPrivate() {
+		//k_i = IL + k mod n
+		d := IL.Add(IL, k.PrivateKey.D)
+		d.Mod(d, curve.Params().N)
+
+		key, err := ecdsa.NewPrivateKey(curve, d)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidChild, err.Error())
+		}
+
+		child.PrivateKey = key
+		child.PublicKey = key.PK
+
+		return child, nil
+	}
+
+	//K_i = point(IL) + K
+	point, err := ecdsa.NewPrivateKey(curve, IL)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidChild, err.Error())
+	}
+
+	x, y := curve.Add(point.PK.X, point.PK.Y, k.PublicKey.X, k.PublicKey.Y)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, ErrInvalidChild
+	}
+
+	child.PublicKey = ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+
+	return child, nil
+}
+
+// DerivePath derives key by path like m/44'/0'/0'/0/0, see ParsePath
+func (k *ExtendedKey) DerivePath(path string) (*ExtendedKey, error) {
+	indexes, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := k
+	for _, index := range indexes {
+		key, err = key.Child(index)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to derive child %d", index)
+		}
+	}
+
+	return key, nil
+}
+
+// ParsePath path starts with m, hardened index is marked with ', h or H suffix
+func ParsePath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, ErrInvalidPath
+	}
+
+	indexes := make([]uint32, 0, len(parts)-1)
+
+	for _, part := range parts[1:] {
+		offset := uint32(0)
+
+		if trimmed := strings.TrimRight(part, "'hH"); len(trimmed) == len(part)-1 {
+			part = trimmed
+			offset = HardenedOffset
+		}
+
+		// only plain decimal numbers, without signs and spaces
+		if part == "" || strings.TrimLeft(part, "0123456789") != "" {
+			return nil, ErrInvalidPath
+		}
+
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint32(index) >= HardenedOffset {
+			return nil, ErrInvalidPath
+		}
+
+		indexes = append(indexes, uint32(index)+offset)
+	}
+
+	return indexes, nil
+}
+
+// String Base58Check serialization, xprv... for private key and xpub... for public one
+func (k *ExtendedKey) String() string {
+	data := make([]byte, 0, serializedSize)
+
+	if k.IsPrivate() {
+		data = append(data, versionPrivate...)
+	} else {
+		data = append(data, versionPublic...)
+	}
+
+	data = append(data, k.Depth)
+	data = append(data, k.ParentFingerprint...)
+	data = binary.BigEndian.AppendUint32(data, k.ChildNumber)
+	data = append(data, k.ChainCode...)
+
+	if k.IsPrivate() {
+		data = append(data, 0)
+		data = append(data, k.PrivateKey.D.FillBytes(make([]byte, 32))...)
+	} else {
+		data = append(data, k.PublicKey.MarshalCompressed(curve)...)
+	}
+
+	return base58.CheckEncode(data)
+}
+
+// ParseExtendedKey parses xprv or xpub and validates key data
+func ParseExtendedKey(s string) (*ExtendedKey, error) {
+	data, err := base58.CheckDecode(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode extended key")
+	}
+
+	if len(data) != serializedSize {
+		return nil, ErrInvalidKeyLength
+	}
+
+	version, keyData := data[:4], data[45:]
+
+	key := &ExtendedKey{
+		Depth:             data[4],
+		ParentFingerprint: append([]byte(nil), data[5:9]...),
+		ChildNumber:       binary.BigEndian.Uint32(data[9:13]),
+		ChainCode:         append([]byte(nil), data[13:45]...),
+	}
+
+	if key.Depth == 0 && (!bytes.Equal(key.ParentFingerprint, make([]byte, 4)) || key.ChildNumber != 0) {
+		return nil, ErrInvalidMasterKey
+	}
+
+	switch {
+	case bytes.Equal(version, versionPrivate):
+		if keyData[0] != 0 {
+			return nil, ErrInvalidPrivateKey
+		}
+
+		privateKey, err := ecdsa.NewPrivateKey(curve, new(big.Int).SetBytes(keyData[1:]))
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidPrivateKey, err.Error())
+		}
+
+		key.PrivateKey = privateKey
+		key.PublicKey = privateKey.PK
+	case bytes.Equal(version, versionPublic):
+		// only compressed point is allowed
+		if keyData[0] != 0x02 && keyData[0] != 0x03 {
+			return nil, ErrInvalidPublicKey
+		}
+
+		publicKey, err := ecdsa.ParsePublicKey(curve, keyData)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidPublicKey, err.Error())
+		}
+
+		key.PublicKey = *publicKey
+	default:
+		return nil, ErrUnknownVersion
+	}
+
+	return key, nil
+}
+
+// hash160 RIPEMD160(SHA256(data))
+func hash160(data []byte) []byte {
+	sha := sha256.Compute(data)
+	result := ripemd160.Compute(sha[:])
+
+	return result[:]
+}
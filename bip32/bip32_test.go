@@ -0,0 +1,313 @@
+package bip32
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mhrynenko/cryptography_course/base58"
+	"github.com/pkg/errors"
+)
+
+type Derivation struct {
+	path string
+	xpub string
+	xprv string
+}
+
+type Vector struct {
+	seed        string
+	derivations []Derivation
+}
+
+// official test vectors 1-4 from BIP-32
+var vectors = []Vector{
+	{
+		seed: "000102030405060708090a0b0c0d0e0f",
+		derivations: []Derivation{
+			{
+				path: "m",
+				xpub: "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+				xprv: "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+			},
+			{
+				path: "m/0H",
+				xpub: "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+				xprv: "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+			},
+			{
+				path: "m/0H/1",
+				xpub: "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+				xprv: "xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+			},
+			{
+				path: "m/0H/1/2H",
+				xpub: "xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+				xprv: "xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM",
+			},
+			{
+				path: "m/0H/1/2H/2",
+				xpub: "xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+				xprv: "xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334",
+			},
+			{
+				path: "m/0H/1/2H/2/1000000000",
+				xpub: "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+				xprv: "xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76",
+			},
+		},
+	},
+	{
+		seed: "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+		derivations: []Derivation{
+			{
+				path: "m",
+				xpub: "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB",
+				xprv: "xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U",
+			},
+			{
+				path: "m/0",
+				xpub: "xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH",
+				xprv: "xprv9vHkqa6EV4sPZHYqZznhT2NPtPCjKuDKGY38FBWLvgaDx45zo9WQRUT3dKYnjwih2yJD9mkrocEZXo1ex8G81dwSM1fwqWpWkeS3v86pgKt",
+			},
+			{
+				path: "m/0/2147483647H",
+				xpub: "xpub6ASAVgeehLbnwdqV6UKMHVzgqAG8Gr6riv3Fxxpj8ksbH9ebxaEyBLZ85ySDhKiLDBrQSARLq1uNRts8RuJiHjaDMBU4Zn9h8LZNnBC5y4a",
+				xprv: "xprv9wSp6B7kry3Vj9m1zSnLvN3xH8RdsPP1Mh7fAaR7aRLcQMKTR2vidYEeEg2mUCTAwCd6vnxVrcjfy2kRgVsFawNzmjuHc2YmYRmagcEPdU9",
+			},
+			{
+				path: "m/0/2147483647H/1",
+				xpub: "xpub6DF8uhdarytz3FWdA8TvFSvvAh8dP3283MY7p2V4SeE2wyWmG5mg5EwVvmdMVCQcoNJxGoWaU9DCWh89LojfZ537wTfunKau47EL2dhHKon",
+				xprv: "xprv9zFnWC6h2cLgpmSA46vutJzBcfJ8yaJGg8cX1e5StJh45BBciYTRXSd25UEPVuesF9yog62tGAQtHjXajPPdbRCHuWS6T8XA2ECKADdw4Ef",
+			},
+			{
+				path: "m/0/2147483647H/1/2147483646H",
+				xpub: "xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL",
+				xprv: "xprvA1RpRA33e1JQ7ifknakTFpgNXPmW2YvmhqLQYMmrj4xJXXWYpDPS3xz7iAxn8L39njGVyuoseXzU6rcxFLJ8HFsTjSyQbLYnMpCqE2VbFWc",
+			},
+			{
+				path: "m/0/2147483647H/1/2147483646H/2",
+				xpub: "xpub6FnCn6nSzZAw5Tw7cgR9bi15UV96gLZhjDstkXXxvCLsUXBGXPdSnLFbdpq8p9HmGsApME5hQTZ3emM2rnY5agb9rXpVGyy3bdW6EEgAtqt",
+				xprv: "xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j",
+			},
+		},
+	},
+	{
+		// retention of leading zeros of private key
+		seed: "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+		derivations: []Derivation{
+			{
+				path: "m",
+				xpub: "xpub661MyMwAqRbcEZVB4dScxMAdx6d4nFc9nvyvH3v4gJL378CSRZiYmhRoP7mBy6gSPSCYk6SzXPTf3ND1cZAceL7SfJ1Z3GC8vBgp2epUt13",
+				xprv: "xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6",
+			},
+			{
+				path: "m/0H",
+				xpub: "xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y",
+				xprv: "xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L",
+			},
+		},
+	},
+	{
+		// retention of leading zeros in hardened derivation
+		seed: "3ddd5602285899a946114506157c7997e5444528f3003f6134712147db19b678",
+		derivations: []Derivation{
+			{
+				path: "m",
+				xpub: "xpub661MyMwAqRbcGczjuMoRm6dXaLDEhW1u34gKenbeYqAix21mdUKJyuyu5F1rzYGVxyL6tmgBUAEPrEz92mBXjByMRiJdba9wpnN37RLLAXa",
+				xprv: "xprv9s21ZrQH143K48vGoLGRPxgo2JNkJ3J3fqkirQC2zVdk5Dgd5w14S7fRDyHH4dWNHUgkvsvNDCkvAwcSHNAQwhwgNMgZhLtQC63zxwhQmRv",
+			},
+			{
+				path: "m/0H",
+				xpub: "xpub69AUMk3qDBi3uW1sXgjCmVjJ2G6WQoYSnNHyzkmdCHEhSZ4tBok37xfFEqHd2AddP56Tqp4o56AePAgCjYdvpW2PU2jbUPFKsav5ut6Ch1m",
+				xprv: "xprv9vB7xEWwNp9kh1wQRfCCQMnZUEG21LpbR9NPCNN1dwhiZkjjeGRnaALmPXCX7SgjFTiCTT6bXes17boXtjq3xLpcDjzEuGLQBM5ohqkao9G",
+			},
+			{
+				path: "m/0H/1H",
+				xpub: "xpub6BJA1jSqiukeaesWfxe6sNK9CCGaujFFSJLomWHprUL9DePQ4JDkM5d88n49sMGJxrhpjazuXYWdMf17C9T5XnxkopaeS7jGk1GyyVziaMt",
+				xprv: "xprv9xJocDuwtYCMNAo3Zw76WENQeAS6WGXQ55RCy7tDJ8oALr4FWkuVoHJeHVAcAqiZLE7Je3vZJHxspZdFHfnBEjHqU5hG1Jaj32dVoS6XLT1",
+			},
+		},
+	},
+}
+
+func TestVectors(t *testing.T) {
+	for i, vector := range vectors {
+		master, err := NewMasterKey(common.Hex2Bytes(vector.seed))
+		if err != nil {
+			t.Fatalf("bip32.NewMasterKey: unexcpected error `%s`", err.Error())
+		}
+
+		for _, derivation := range vector.derivations {
+			key, err := master.DerivePath(derivation.path)
+			if err != nil {
+				t.Fatalf("bip32.DerivePath: unexcpected error `%s` for %s", err.Error(), derivation.path)
+			}
+
+			if key.String() != derivation.xprv {
+				t.Errorf("bip32.DerivePath: wrong xprv for %s in %d vector", derivation.path, i)
+			}
+
+			if key.Neuter().String() != derivation.xpub {
+				t.Errorf("bip32.Neuter: wrong xpub for %s in %d vector", derivation.path, i)
+			}
+
+			for _, serialized := range []string{derivation.xprv, derivation.xpub} {
+				parsed, err := ParseExtendedKey(serialized)
+				if err != nil {
+					t.Fatalf("bip32.ParseExtendedKey: unexcpected error `%s` for %s", err.Error(), derivation.path)
+				}
+
+				if parsed.String() != serialized {
+					t.Errorf("bip32.ParseExtendedKey: key is not restored for %s in %d vector", derivation.path, i)
+				}
+			}
+		}
+	}
+}
+
+// TestPublicDerivation non-hardened children of xpub must match public keys of xprv children
+func TestPublicDerivation(t *testing.T) {
+	master, err := NewMasterKey(common.Hex2Bytes(vectors[0].seed))
+	if err != nil {
+		t.Fatalf("bip32.NewMasterKey: unexcpected error `%s`", err.Error())
+	}
+
+	account, err := master.DerivePath("m/44'/0'/0'")
+	if err != nil {
+		t.Fatalf("bip32.DerivePath: unexcpected error `%s`", err.Error())
+	}
+
+	for _, path := range []string{"m/0", "m/0/0", "m/1/5", "m/0/2147483647"} {
+		private, err := account.DerivePath(path)
+		if err != nil {
+			t.Fatalf("bip32.DerivePath: unexcpected error `%s`", err.Error())
+		}
+
+		public, err := account.Neuter().DerivePath(path)
+		if err != nil {
+			t.Fatalf("bip32.DerivePath: unexcpected error `%s`", err.Error())
+		}
+
+		if private.Neuter().String() != public.String() {
+			t.Errorf("bip32.Child: public derivation differs from private one for %s", path)
+		}
+	}
+
+	if _, err := account.Neuter().DerivePath("m/0/1'"); errors.Cause(err) != ErrHardenedFromPublic {
+		t.Errorf("bip32.Child: expected error for hardened child of public key")
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	indexes, err := ParsePath("m/44'/0h/0H/0/2147483647")
+	if err != nil {
+		t.Fatalf("bip32.ParsePath: unexcpected error `%s`", err.Error())
+	}
+
+	expected := []uint32{HardenedOffset + 44, HardenedOffset, HardenedOffset, 0, 2147483647}
+	for i := range expected {
+		if len(indexes) != len(expected) || indexes[i] != expected[i] {
+			t.Fatalf("bip32.ParsePath: wrong indexes %v", indexes)
+		}
+	}
+
+	if indexes, err := ParsePath("m"); err != nil || len(indexes) != 0 {
+		t.Errorf("bip32.ParsePath: wrong result for master key path")
+	}
+
+	for _, path := range []string{"", "/0", "M/0", "m/", "m//0", "m/-1", "m/+1", "m/0''", "m/2147483648", "m/1a", "m/ 1", "n/0"} {
+		if _, err := ParsePath(path); err != ErrInvalidPath {
+			t.Errorf("bip32.ParsePath: expected error for `%s`", path)
+		}
+	}
+}
+
+type InvalidKeyVector struct {
+	name string
+	key  string
+	err  error
+}
+
+// invalidKeys Test vector 5 from BIP-32, names are taken from it
+var invalidKeys = []InvalidKeyVector{
+	{name: "pubkey version / prvkey mismatch", key: "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6LBpB85b3D2yc8sfvZU521AAwdZafEz7mnzBBsz4wKY5fTtTQBm", err: ErrInvalidPublicKey},
+	{name: "prvkey version / pubkey mismatch", key: "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGTQQD3dC4H2D5GBj7vWvSQaaBv5cxi9gafk7NF3pnBju6dwKvH", err: ErrInvalidPrivateKey},
+	{name: "invalid pubkey prefix 04", key: "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Txnt3siSujt9RCVYsx4qHZGc62TG4McvMGcAUjeuwZdduYEvFn", err: ErrInvalidPublicKey},
+	{name: "invalid prvkey prefix 04", key: "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGpWnsj83BHtEy5Zt8CcDr1UiRXuWCmTQLxEK9vbz5gPstX92JQ", err: ErrInvalidPrivateKey},
+	{name: "invalid pubkey prefix 01", key: "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6N8ZMMXctdiCjxTNq964yKkwrkBJJwpzZS4HS2fxvyYUA4q2Xe4", err: ErrInvalidPublicKey},
+	{name: "invalid prvkey prefix 01", key: "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD9y5gkZ6Eq3Rjuahrv17fEQ3Qen6J", err: ErrInvalidPrivateKey},
+	{name: "zero depth with non-zero parent fingerprint", key: "xprv9s2SPatNQ9Vc6GTbVMFPFo7jsaZySyzk7L8n2uqKXJen3KUmvQNTuLh3fhZMBoG3G4ZW1N2kZuHEPY53qmbZzCHshoQnNf4GvELZfqTUrcv", err: ErrInvalidMasterKey},
+	{name: "zero depth with non-zero parent fingerprint", key: "xpub661no6RGEX3uJkY4bNnPcw4URcQTrSibUZ4NqJEw5eBkv7ovTwgiT91XX27VbEXGENhYRCf7hyEbWrR3FewATdCEebj6znwMfQkhRYHRLpJ", err: ErrInvalidMasterKey},
+	{name: "zero depth with non-zero index", key: "xprv9s21ZrQH4r4TsiLvyLXqM9P7k1K3EYhA1kkD6xuquB5i39AU8KF42acDyL3qsDbU9NmZn6MsGSUYZEsuoePmjzsB3eFKSUEh3Gu1N3cqVUN", err: ErrInvalidMasterKey},
+	{name: "zero depth with non-zero index", key: "xpub661MyMwAuDcm6CRQ5N4qiHKrJ39Xe1R1NyfouMKTTWcguwVcfrZJaNvhpebzGerh7gucBvzEQWRugZDuDXjNDRmXzSZe4c7mnTK97pTvGS8", err: ErrInvalidMasterKey},
+	{name: "unknown extended key version", key: "DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHGMQzT7ayAmfo4z3gY5KfbrZWZ6St24UVf2Qgo6oujFktLHdHY4", err: ErrUnknownVersion},
+	{name: "unknown extended key version", key: "DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHPmHJiEDXkTiJTVV9rHEBUem2mwVbbNfvT2MTcAqj3nesx8uBf9", err: ErrUnknownVersion},
+	{name: "private key 0 not in 1..n-1", key: "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzF93Y5wvzdUayhgkkFoicQZcP3y52uPPxFnfoLZB21Teqt1VvEHx", err: ErrInvalidPrivateKey},
+	{name: "private key n not in 1..n-1", key: "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD5SDKr24z3aiUvKr9bJpdrcLg1y3G", err: ErrInvalidPrivateKey},
+	{name: "invalid pubkey 020000000000000000000000000000000000000000000000000000000000000007", key: "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Q5JXayek4PRsn35jii4veMimro1xefsM58PgBMrvdYre8QyULY", err: ErrInvalidPublicKey},
+	{name: "invalid checksum", key: "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHL", err: base58.ErrInvalidChecksum},
+}
+
+func TestVector5(t *testing.T) {
+	for _, vector := range invalidKeys {
+		if _, err := ParseExtendedKey(vector.key); errors.Cause(err) != vector.err {
+			t.Errorf("bip32.ParseExtendedKey: wrong error for %s, err = %v", vector.name, err)
+		}
+	}
+}
+
+func TestInvalidKeys(t *testing.T) {
+	if _, err := NewMasterKey(make([]byte, 15)); err != ErrInvalidSeedLength {
+		t.Errorf("bip32.NewMasterKey: expected error for short seed")
+	}
+
+	if _, err := NewMasterKey(make([]byte, 65)); err != ErrInvalidSeedLength {
+		t.Errorf("bip32.NewMasterKey: expected error for long seed")
+	}
+
+	master, err := NewMasterKey(common.Hex2Bytes(vectors[0].seed))
+	if err != nil {
+		t.Fatalf("bip32.NewMasterKey: unexcpected error `%s`", err.Error())
+	}
+
+	child, err := master.Child(1)
+	if err != nil {
+		t.Fatalf("bip32.Child: unexcpected error `%s`", err.Error())
+	}
+
+	serialize := func(key *ExtendedKey, modify func(data []byte)) string {
+		data, err := base58.CheckDecode(key.String())
+		if err != nil {
+			t.Fatalf("base58.CheckDecode: unexcpected error `%s`", err.Error())
+		}
+
+		modify(data)
+
+		return base58.CheckEncode(data)
+	}
+
+	cases := []struct {
+		name string
+		key  string
+		err  error
+	}{
+		{name: "unknown version", key: serialize(master, func(data []byte) { data[3] = 0 }), err: ErrUnknownVersion},
+		{name: "master with fingerprint", key: serialize(master, func(data []byte) { data[5] = 1 }), err: ErrInvalidMasterKey},
+		{name: "master with index", key: serialize(master, func(data []byte) { data[12] = 1 }), err: ErrInvalidMasterKey},
+		{name: "private key prefix", key: serialize(child, func(data []byte) { data[45] = 1 }), err: ErrInvalidPrivateKey},
+		{name: "zero private key", key: serialize(child, func(data []byte) { copy(data[46:], make([]byte, 32)) }), err: ErrInvalidPrivateKey},
+		{name: "private key equal to N", key: serialize(child, func(data []byte) { curve.Params().N.FillBytes(data[46:]) }), err: ErrInvalidPrivateKey},
+		{name: "public key prefix", key: serialize(child.Neuter(), func(data []byte) { data[45] = 4 }), err: ErrInvalidPublicKey},
+		{name: "public key not on curve", key: serialize(child.Neuter(), func(data []byte) { copy(data[46:], make([]byte, 32)) }), err: ErrInvalidPublicKey},
+	}
+
+	for _, c := range cases {
+		if _, err := ParseExtendedKey(c.key); errors.Cause(err) != c.err {
+			t.Errorf("bip32.ParseExtendedKey: wrong error for %s, err = %v", c.name, err)
+		}
+	}
+
+	if _, err := ParseExtendedKey(vectors[0].derivations[0].xprv + "1"); err == nil {
+		t.Errorf("bip32.ParseExtendedKey: expected error for invalid checksum")
+	}
+}
@@ -0,0 +1,142 @@
+package bip39
+
+import (
+	"crypto/rand"
+	"strings"
+
+	"github.com/mhrynenko/cryptography_course/pbkdf2"
+	"github.com/mhrynenko/cryptography_course/sha256"
+	"github.com/mhrynenko/cryptography_course/sha512"
+	"github.com/pkg/errors"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// bitsPerWord wordlist has 2048 words
+	bitsPerWord = 11
+
+	seedIterations = 2048
+	SeedSize       = 64
+)
+
+var (
+	ErrInvalidEntropyLength  = errors.New("entropy must be from 128 to 256 bits and multiple of 32 bits")
+	ErrInvalidMnemonicLength = errors.New("mnemonic must have 12, 15, 18, 21 or 24 words")
+	ErrUnknownWord           = errors.New("word is not in the wordlist")
+	ErrInvalidChecksum       = errors.New("mnemonic checksum is invalid")
+)
+
+// NewEntropy random entropy of bitSize bits, 128 bits give 12 words, 256 bits give 24 words
+func NewEntropy(bitSize int) ([]byte, error) {
+	if err := validateEntropyLength(bitSize); err != nil {
+		return nil, err
+	}
+
+	entropy := make([]byte, bitSize/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return nil, errors.Wrap(err, "failed to generate entropy")
+	}
+
+	return entropy, nil
+}
+
+// NewMnemonic ENT bits of entropy and ENT/32 bits of SHA256(entropy) checksum are split into 11-bit word indexes
+func NewMnemonic(entropy []byte) (string, error) {
+	entropyBits := len(entropy) * 8
+	if err := validateEntropyLength(entropyBits); err != nil {
+		return "", err
+	}
+
+	checksum := sha256.Compute(entropy)
+	data := append(append([]byte(nil), entropy...), checksum[0])
+
+	wordsAmount := (entropyBits + entropyBits/32) / bitsPerWord
+	words := make([]string, wordsAmount)
+
+	for i := range words {
+		index := 0
+		for j := 0; j < bitsPerWord; j++ {
+			index = index<<1 | getBit(data, i*bitsPerWord+j)
+		}
+
+		words[i] = English[index]
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// EntropyFromMnemonic restores entropy and verifies checksum
+func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
+		return nil, ErrInvalidMnemonicLength
+	}
+
+	totalBits := len(words) * bitsPerWord
+	checksumBits := totalBits / 33
+	entropyBits := totalBits - checksumBits
+
+	// one more byte for checksum, it is at most 8 bits
+	data := make([]byte, entropyBits/8+1)
+
+	for i, word := range words {
+		index, ok := englishIndexes[word]
+		if !ok {
+			return nil, errors.Wrapf(ErrUnknownWord, "word %d `%s`", i+1, word)
+		}
+
+		for j := 0; j < bitsPerWord; j++ {
+			setBit(data, i*bitsPerWord+j, (index>>(bitsPerWord-1-j))&1)
+		}
+	}
+
+	entropy := data[:entropyBits/8]
+
+	//checksum is the first ENT/32 bits of SHA256(entropy)
+	checksum := sha256.Compute(entropy)
+	mask := byte(0xff) << (8 - checksumBits)
+
+	if data[len(data)-1]&mask != checksum[0]&mask {
+		return nil, ErrInvalidChecksum
+	}
+
+	return entropy, nil
+}
+
+func IsMnemonicValid(mnemonic string) bool {
+	_, err := EntropyFromMnemonic(mnemonic)
+	return err == nil
+}
+
+// NewSeed PBKDF2-HMAC-SHA512(mnemonic, "mnemonic" + passphrase, 2048 iterations), the result can be used with
+// bip32.NewMasterKey. Both strings are NFKD normalized as BIP-39 requires, so composed and decomposed forms
+// of non-ASCII passphrase give the same seed
+func NewSeed(mnemonic, passphrase string) ([]byte, error) {
+	normalized := strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+
+	if _, err := EntropyFromMnemonic(normalized); err != nil {
+		return nil, err
+	}
+
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+
+	return pbkdf2.Key(sha512.New, []byte(normalized), []byte(salt), seedIterations, SeedSize), nil
+}
+
+func validateEntropyLength(bitSize int) error {
+	if bitSize < 128 || bitSize > 256 || bitSize%32 != 0 {
+		return ErrInvalidEntropyLength
+	}
+
+	return nil
+}
+
+// getBit i-th bit of data, the most significant bit of the first byte is 0-th
+func getBit(data []byte, i int) int {
+	return int(data[i/8]>>(7-i%8)) & 1
+}
+
+func setBit(data []byte, i, bit int) {
+	data[i/8] |= byte(bit) << (7 - i%8)
+}
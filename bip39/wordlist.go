@@ -0,0 +1,22 @@
+package bip39
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// englishWords official BIP-39 English wordlist, sorted, every word is uniquely identified by its first 4 letters
+//
+//go:embed english.txt
+var englishWords string
+
+var English = strings.Split(strings.TrimSpace(englishWords), "\n")
+
+var englishIndexes = func() map[string]int {
+	result := make(map[string]int, len(English))
+	for i, word := range English {
+		result[word] = i
+	}
+
+	return result
+}()
@@ -0,0 +1,122 @@
+package ecdh
+
+import (
+	"crypto/elliptic"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/mhrynenko/cryptography_course/ecdsa"
+	"github.com/mhrynenko/cryptography_course/sha256"
+	"github.com/pkg/errors"
+)
+
+// errors shared with ecdsa keep their identity, so callers can match them with ecdsa ones
+var (
+	ErrNilPrivateKey         = ecdsa.ErrNilPrivateKey
+	ErrInvalidPrivateKey     = ecdsa.ErrNumberIsOutOfRange
+	ErrNilPublicKey          = ecdsa.ErrNilPublicKey
+	ErrZeroPublicKey         = ecdsa.ErrZeroPublicKey
+	ErrPublicKeyIsNotOnCurve = ecdsa.ErrPublicKeyIsNotOnCurve
+)
+
+var (
+	ErrWrongSubgroup    = errors.New("public key is not in the subgroup of order N")
+	ErrZeroSharedSecret = errors.New("shared secret is the point at infinity")
+	ErrKeyLengthTooBig  = errors.New("requested key length is too big")
+)
+
+// SharedSecret computes d x Q and returns its X coordinate padded to the field size (as in SEC1 and crypto/ecdh)
+func SharedSecret(curve elliptic.Curve, key *ecdsa.PrivateKey, Q ecdsa.PublicKey) ([]byte, error) {
+	if key == nil || key.D == nil {
+		return nil, ErrNilPrivateKey
+	}
+
+	//1 <= d <= n - 1
+	N := curve.Params().N
+	if key.D.Sign() <= 0 || key.D.Cmp(N) >= 0 {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	if err := ValidatePublicKey(curve, Q); err != nil {
+		return nil, err
+	}
+
+	//d x Q = (x, y), computed in constant time, so d does not leak through timing
+	x, y := ecdsa.ScalarMult(curve, Q.X, Q.Y, key.D)
+
+	if isInfinity(x, y) {
+		return nil, ErrZeroSharedSecret
+	}
+
+	return x.FillBytes(make([]byte, (curve.Params().BitSize+7)/8)), nil
+}
+
+// ValidatePublicKey full public key validation (SEC1 3.2.2.1): Q is not the point at infinity,
+// its coordinates are in [0, p - 1], it is on curve and N x Q is the point at infinity
+func ValidatePublicKey(curve elliptic.Curve, Q ecdsa.PublicKey) error {
+	if Q.X == nil || Q.Y == nil {
+		return ErrNilPublicKey
+	}
+
+	if isInfinity(Q.X, Q.Y) {
+		return ErrZeroPublicKey
+	}
+
+	P := curve.Params().P
+	if Q.X.Sign() < 0 || Q.X.Cmp(P) >= 0 || Q.Y.Sign() < 0 || Q.Y.Cmp(P) >= 0 {
+		return ErrPublicKeyIsNotOnCurve
+	}
+
+	if !curve.IsOnCurve(Q.X, Q.Y) {
+		return ErrPublicKeyIsNotOnCurve
+	}
+
+	// all supported curves have cofactor 1, so this check never fails for point on curve,
+	// but it is cheap insurance against curves with cofactor
+	x, y := curve.ScalarMult(Q.X, Q.Y, curve.Params().N.Bytes())
+	if !isInfinity(x, y) {
+		return ErrWrongSubgroup
+	}
+
+	return nil
+}
+
+// KDF ANSI X9.63 key derivation function over SHA256: K = H(Z || 1 || info) || H(Z || 2 || info) || ...,
+// counter is 32-bit big endian number
+func KDF(secret, info []byte, length int) ([]byte, error) {
+	// counter is limited to 2^32 - 1 blocks
+	if uint64(length) > uint64(sha256.Size)*(1<<32-1) {
+		return nil, ErrKeyLengthTooBig
+	}
+
+	result := make([]byte, 0, length+sha256.Size)
+	h := sha256.New()
+
+	var counter [4]byte
+	for i := uint32(1); len(result) < length; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+
+		h.Reset()
+		h.Write(secret)
+		h.Write(counter[:])
+		h.Write(info)
+
+		result = h.Sum(result)
+	}
+
+	return result[:length], nil
+}
+
+// SharedKey computes shared secret and derives key of required length from it with KDF
+func SharedKey(curve elliptic.Curve, key *ecdsa.PrivateKey, Q ecdsa.PublicKey, info []byte, length int) ([]byte, error) {
+	secret, err := SharedSecret(curve, key, Q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute shared secret")
+	}
+
+	return KDF(secret, info, length)
+}
+
+func isInfinity(x, y *big.Int) bool {
+	return len(x.Bits()) == 0 && len(y.Bits()) == 0
+}
@@ -0,0 +1,203 @@
+package ecdh
+
+import (
+	stdecdh "crypto/ecdh"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mhrynenko/cryptography_course/ecdsa"
+	"github.com/mhrynenko/cryptography_course/secp256k1"
+)
+
+func TestSharedSecret(t *testing.T) {
+	curves := []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521(), secp256k1.S256()}
+
+	for _, curve := range curves {
+		name := curve.Params().Name
+
+		alice, err := ecdsa.GeneratePrivateKey(curve)
+		if err != nil {
+			t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		bob, err := ecdsa.GeneratePrivateKey(curve)
+		if err != nil {
+			t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		aliceSecret, err := SharedSecret(curve, alice, bob.PK)
+		if err != nil {
+			t.Fatalf("ecdh.SharedSecret: unexcpected error `%s` on %s", err.Error(), name)
+		}
+
+		bobSecret, err := SharedSecret(curve, bob, alice.PK)
+		if err != nil {
+			t.Fatalf("ecdh.SharedSecret: unexcpected error `%s` on %s", err.Error(), name)
+		}
+
+		if common.Bytes2Hex(aliceSecret) != common.Bytes2Hex(bobSecret) {
+			t.Errorf("ecdh.SharedSecret: secrets do not match on %s", name)
+		}
+
+		if len(aliceSecret) != (curve.Params().BitSize+7)/8 {
+			t.Errorf("ecdh.SharedSecret: wrong secret length %d on %s", len(aliceSecret), name)
+		}
+
+		aliceKey, err := SharedKey(curve, alice, bob.PK, []byte("info"), 48)
+		if err != nil {
+			t.Fatalf("ecdh.SharedKey: unexcpected error `%s` on %s", err.Error(), name)
+		}
+
+		bobKey, err := SharedKey(curve, bob, alice.PK, []byte("info"), 48)
+		if err != nil {
+			t.Fatalf("ecdh.SharedKey: unexcpected error `%s` on %s", err.Error(), name)
+		}
+
+		if len(aliceKey) != 48 || common.Bytes2Hex(aliceKey) != common.Bytes2Hex(bobKey) {
+			t.Errorf("ecdh.SharedKey: keys do not match on %s", name)
+		}
+	}
+}
+
+func TestStdlibECDH(t *testing.T) {
+	curve := elliptic.P256()
+
+	for i := 0; i < 20; i++ {
+		stdAlice, err := stdecdh.P256().GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("ecdh.GenerateKey: unexcpected error `%s`", err.Error())
+		}
+
+		bob, err := ecdsa.GeneratePrivateKey(curve)
+		if err != nil {
+			t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		stdBobPub, err := stdecdh.P256().NewPublicKey(bob.PK.Marshal(curve))
+		if err != nil {
+			t.Fatalf("ecdh.NewPublicKey: unexcpected error `%s`", err.Error())
+		}
+
+		expected, err := stdAlice.ECDH(stdBobPub)
+		if err != nil {
+			t.Fatalf("ecdh.ECDH: unexcpected error `%s`", err.Error())
+		}
+
+		alicePub, err := ecdsa.ParsePublicKey(curve, stdAlice.PublicKey().Bytes())
+		if err != nil {
+			t.Fatalf("ecdsa.ParsePublicKey: unexcpected error `%s`", err.Error())
+		}
+
+		// our side of Bob with std Alice public key
+		secret, err := SharedSecret(curve, bob, *alicePub)
+		if err != nil {
+			t.Fatalf("ecdh.SharedSecret: unexcpected error `%s`", err.Error())
+		}
+
+		if common.Bytes2Hex(secret) != common.Bytes2Hex(expected) {
+			t.Errorf("ecdh.SharedSecret: secret does not match crypto/ecdh for %d vector", i)
+		}
+
+		// our side of Alice with std private key
+		alice := &ecdsa.PrivateKey{PK: *alicePub, D: new(big.Int).SetBytes(stdAlice.Bytes())}
+
+		secret, err = SharedSecret(curve, alice, bob.PK)
+		if err != nil {
+			t.Fatalf("ecdh.SharedSecret: unexcpected error `%s`", err.Error())
+		}
+
+		if common.Bytes2Hex(secret) != common.Bytes2Hex(expected) {
+			t.Errorf("ecdh.SharedSecret: secret does not match crypto/ecdh for %d vector", i)
+		}
+	}
+}
+
+func TestInvalidKeys(t *testing.T) {
+	curve := elliptic.P256()
+	P := curve.Params().P
+
+	key, err := ecdsa.GeneratePrivateKey(curve)
+	if err != nil {
+		t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+	}
+
+	publicKeys := []struct {
+		name string
+		Q    ecdsa.PublicKey
+		err  error
+	}{
+		{name: "nil public key", Q: ecdsa.PublicKey{}, err: ErrNilPublicKey},
+		{name: "point at infinity", Q: ecdsa.PublicKey{X: big.NewInt(0), Y: big.NewInt(0)}, err: ErrZeroPublicKey},
+		// the same error as in ecdsa, so callers can handle both packages in one place
+		{name: "not on curve", Q: ecdsa.PublicKey{X: key.PK.X, Y: new(big.Int).Add(key.PK.Y, big.NewInt(1))}, err: ecdsa.ErrPublicKeyIsNotOnCurve},
+		{name: "x + p", Q: ecdsa.PublicKey{X: new(big.Int).Add(key.PK.X, P), Y: key.PK.Y}, err: ErrPublicKeyIsNotOnCurve},
+		{name: "negative y", Q: ecdsa.PublicKey{X: key.PK.X, Y: new(big.Int).Sub(key.PK.Y, P)}, err: ErrPublicKeyIsNotOnCurve},
+	}
+
+	for _, c := range publicKeys {
+		if _, err := SharedSecret(curve, key, c.Q); err != c.err {
+			t.Errorf("ecdh.SharedSecret: wrong error for %s, err = %v", c.name, err)
+		}
+	}
+
+	privateKeys := []struct {
+		name string
+		key  *ecdsa.PrivateKey
+		err  error
+	}{
+		{name: "nil key", key: nil, err: ErrNilPrivateKey},
+		{name: "nil d", key: &ecdsa.PrivateKey{}, err: ErrNilPrivateKey},
+		{name: "zero d", key: &ecdsa.PrivateKey{D: big.NewInt(0)}, err: ErrInvalidPrivateKey},
+		{name: "d = n", key: &ecdsa.PrivateKey{D: curve.Params().N}, err: ecdsa.ErrNumberIsOutOfRange},
+	}
+
+	for _, c := range privateKeys {
+		if _, err := SharedSecret(curve, c.key, key.PK); err != c.err {
+			t.Errorf("ecdh.SharedSecret: wrong error for %s, err = %v", c.name, err)
+		}
+	}
+}
+
+type KDFVector struct {
+	secret string
+	info   string
+	key    string
+}
+
+// ANSI X9.63 SHA256 vectors from NIST CAVS
+var kdfVectors = []KDFVector{
+	{
+		secret: "96c05619d56c328ab95fe84b18264b08725b85e33fd34f08",
+		info:   "",
+		key:    "443024c3dae66b95e6f5670601558f71",
+	},
+	{
+		secret: "22518b10e70f2a3f243810ae3254139efbee04aa57c7af7d",
+		info:   "75eef81aa3041e33b80971203d2c0c52",
+		key: "c498af77161cc59f2962b9a713e2b215152d139766ce34a776df11866a69bf2e52a13d9c7c6fc878c50c5ea0bc7b00e0da" +
+			"2447cfd874f6cf92f30d0097111485500c90c3af8b487872d04685d14c8d1dc8d7fa08beb0ce0ababc11f0bd496269142d" +
+			"43525a78e5bc79a17f59676a5706dc54d54d4d1f0bd7e386128ec26afc21",
+	},
+}
+
+func TestKDF(t *testing.T) {
+	for i, vector := range kdfVectors {
+		expected := common.Hex2Bytes(vector.key)
+
+		key, err := KDF(common.Hex2Bytes(vector.secret), common.Hex2Bytes(vector.info), len(expected))
+		if err != nil {
+			t.Fatalf("ecdh.KDF: unexcpected error `%s`", err.Error())
+		}
+
+		if common.Bytes2Hex(key) != vector.key {
+			t.Errorf("ecdh.KDF: wrong key for %d vector", i)
+		}
+	}
+
+	if _, err := KDF([]byte{1}, nil, -1); err != ErrKeyLengthTooBig {
+		t.Errorf("ecdh.KDF: expected error for negative length")
+	}
+}
@@ -0,0 +1,202 @@
+package ecdsa
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"runtime"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var ErrEmptyBatch = errors.New("batch is empty")
+
+type BatchItem struct {
+	Msg []byte
+	// Digest already computed message hash, when it is set Msg is ignored, e.g. for signatures made by SignDigest
+	// or SignWithOptions with non-default hash
+	Digest    []byte
+	Signature *Signature
+	PublicKey PublicKey
+}
+
+// digest H(m) with hash matching the curve, unless it is passed explicitly
+func (item *BatchItem) digest(curve elliptic.Curve) []byte {
+	if item.Digest != nil {
+		return item.Digest
+	}
+
+	return hashMessage(hashFunc(curve), item.Msg)
+}
+
+type BatchResult struct {
+	Valid bool
+	Err   error
+}
+
+// VerifyBatch verifies every item with Verify across worker pool, workers <= 0 means one worker per CPU
+func VerifyBatch(curve elliptic.Curve, items []BatchItem, workers int) []BatchResult {
+	results := make([]BatchResult, len(items))
+
+	runWorkers(len(items), workers, func(from, to int) {
+		for i := from; i < to; i++ {
+			sig := items[i].Signature
+			if sig == nil {
+				results[i] = BatchResult{Err: ErrNilSignatureValue}
+				continue
+			}
+
+			valid, err := VerifyDigest(curve, items[i].digest(curve), sig.R, sig.S, items[i].PublicKey)
+			results[i] = BatchResult{Valid: valid, Err: err}
+		}
+	})
+
+	return results
+}
+
+// VerifyBatchRandomized checks all signatures at once, it needs recovery ids to restore R_i = k_i x P.
+// Each valid signature gives s_i*R_i = e_i*G + r_i*Q_i, so with random a_i the sum
+// sum(a_i*s_i*R_i) - sum(a_i*r_i*Q_i) must be equal to sum(a_i*e_i)*G. Random a_i make it impossible to craft
+// invalid signatures which cancel each other. It does not tell which signature is invalid, VerifyBatch should be
+// used for that.
+func VerifyBatchRandomized(curve elliptic.Curve, items []BatchItem, workers int) (bool, error) {
+	if len(items) == 0 {
+		return false, ErrEmptyBatch
+	}
+
+	type partialSum struct {
+		x, y   *big.Int
+		gCoeff *big.Int
+		err    error
+	}
+
+	N := curve.Params().N
+	maxValue := new(big.Int).Sub(N, big.NewInt(1))
+
+	var mu sync.Mutex
+	var partials []partialSum
+
+	runWorkers(len(items), workers, func(from, to int) {
+		partial := partialSum{x: new(big.Int), y: new(big.Int), gCoeff: new(big.Int)}
+
+		for i := from; i < to && partial.err == nil; i++ {
+			item := items[i]
+			sig := item.Signature
+
+			if sig == nil || sig.R == nil || sig.S == nil {
+				partial.err = ErrNilSignatureValue
+				break
+			}
+
+			if err := validatePublicKey(curve, item.PublicKey); err != nil {
+				partial.err = err
+				break
+			}
+
+			if !checkBigIntInRange(sig.R, big.NewInt(1), maxValue) || !checkBigIntInRange(sig.S, big.NewInt(1), maxValue) {
+				partial.err = ErrNumberIsOutOfRange
+				break
+			}
+
+			if sig.V > 3 {
+				partial.err = ErrInvalidRecoveryID
+				break
+			}
+
+			//R_i is restored from r and recovery id
+			x := new(big.Int).Set(sig.R)
+			if sig.V&2 != 0 {
+				x.Add(x, N)
+			}
+
+			y, err := decompressPoint(curve, x, sig.V&1 == 1)
+			if err != nil {
+				partial.err = errors.Wrap(ErrInvalidRecoveryID, err.Error())
+				break
+			}
+
+			a, err := randomK(rand.Reader, N)
+			if err != nil {
+				partial.err = err
+				break
+			}
+
+			// H(m)
+			h := bits2int(item.digest(curve), N)
+
+			//a*s*R
+			as := new(big.Int).Mul(a, sig.S)
+			as.Mod(as, N)
+			x1, y1 := curve.ScalarMult(x, y, as.Bytes())
+
+			//-a*r*Q
+			ar := new(big.Int).Mul(a, sig.R)
+			ar.Neg(ar)
+			ar.Mod(ar, N)
+			x2, y2 := curve.ScalarMult(item.PublicKey.X, item.PublicKey.Y, ar.Bytes())
+
+			x1, y1 = curve.Add(x1, y1, x2, y2)
+			partial.x, partial.y = curve.Add(partial.x, partial.y, x1, y1)
+
+			//a*e
+			partial.gCoeff.Add(partial.gCoeff, h.Mul(h, a))
+			partial.gCoeff.Mod(partial.gCoeff, N)
+		}
+
+		mu.Lock()
+		partials = append(partials, partial)
+		mu.Unlock()
+	})
+
+	sumX, sumY := new(big.Int), new(big.Int)
+	gCoeff := new(big.Int)
+
+	for _, partial := range partials {
+		if partial.err != nil {
+			return false, partial.err
+		}
+
+		sumX, sumY = curve.Add(sumX, sumY, partial.x, partial.y)
+		gCoeff.Add(gCoeff, partial.gCoeff)
+	}
+
+	gCoeff.Mod(gCoeff, N)
+	gX, gY := curve.ScalarBaseMult(gCoeff.Bytes())
+
+	return sumX.Cmp(gX) == 0 && sumY.Cmp(gY) == 0, nil
+}
+
+// runWorkers splits [0, amount) into chunks and processes them concurrently
+func runWorkers(amount, workers int, process func(from, to int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	if workers > amount {
+		workers = amount
+	}
+
+	if workers == 0 {
+		return
+	}
+
+	chunk := (amount + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for from := 0; from < amount; from += chunk {
+		to := from + chunk
+		if to > amount {
+			to = amount
+		}
+
+		wg.Add(1)
+
+		go func(from, to int) {
+			defer wg.Done()
+			process(from, to)
+		}(from, to)
+	}
+
+	wg.Wait()
+}
@@ -3,9 +3,11 @@ package ecdsa
 import (
 	"crypto/elliptic"
 	"crypto/rand"
+	"hash"
 	"math/big"
 
 	"github.com/mhrynenko/cryptography_course/sha256"
+	"github.com/mhrynenko/cryptography_course/sha512"
 	"github.com/pkg/errors"
 )
 
@@ -14,11 +16,17 @@ var (
 	ErrPublicKeyIsNotOnCurve = errors.New("public key is not in elliptic curve")
 
 	ErrNumberIsOutOfRange = errors.New("number is out of range")
+	ErrNilSignatureValue  = errors.New("signature value is nil")
+	ErrNilPublicKey       = errors.New("public key coordinate is nil")
+	ErrNilPrivateKey      = errors.New("private key is nil")
+	ErrInvalidNonce       = errors.New("nonce gives zero r or s, another one is required")
 )
 
 type PublicKey struct {
-	X *big.Int
-	Y *big.Int
+	// Curve is set by functions of this package, it is needed for crypto.Signer and crypto/ecdsa interop
+	Curve elliptic.Curve
+	X     *big.Int
+	Y     *big.Int
 }
 
 type PrivateKey struct {
@@ -26,9 +34,30 @@ type PrivateKey struct {
 	D  *big.Int
 }
 
+// Options additional signing and verification settings, nil options mean default behaviour
+type Options struct {
+	// LowS Sign normalizes s to be not bigger than N/2, Verify rejects signatures with s > N/2
+	LowS bool
+	// Hash used for message hashing (and for nonce generation in deterministic mode),
+	// when it is not set, hash matching the curve is used
+	Hash func() hash.Hash
+	// Deterministic k is derived from private key and message hash (RFC 6979) when it is not passed explicitly
+	Deterministic bool
+}
+
+func (opts *Options) hashFunc(curve elliptic.Curve) func() hash.Hash {
+	if opts != nil && opts.Hash != nil {
+		return opts.Hash
+	}
+
+	return hashFunc(curve)
+}
+
 type Signature struct {
 	R *big.Int
 	S *big.Int
+	// V recovery id, bit 0 is parity of k*G Y coordinate, bit 1 is set when its X coordinate is not less than N
+	V byte
 }
 
 func GeneratePrivateKey(curve elliptic.Curve) (*PrivateKey, error) {
@@ -38,7 +67,7 @@ func GeneratePrivateKey(curve elliptic.Curve) (*PrivateKey, error) {
 	}
 	d.Mod(d, curve.Params().N)
 
-	pubX, pubY := curve.ScalarBaseMult(d.Bytes())
+	pubX, pubY := scalarBaseMult(curve, d)
 
 	if len(pubX.Bits()) == 0 && len(pubY.Bits()) == 0 {
 		return nil, ErrZeroPublicKey
@@ -49,76 +78,158 @@ func GeneratePrivateKey(curve elliptic.Curve) (*PrivateKey, error) {
 	}
 
 	return &PrivateKey{
-		PK: PublicKey{X: pubX, Y: pubY},
+		PK: PublicKey{Curve: curve, X: pubX, Y: pubY},
 		D:  d,
 	}, nil
 }
 
+// NewPrivateKey restores key pair from existing private key d, 1 <= d <= n - 1
+func NewPrivateKey(curve elliptic.Curve, d *big.Int) (*PrivateKey, error) {
+	if d == nil || !checkBigIntInRange(d, big.NewInt(1), new(big.Int).Sub(curve.Params().N, big.NewInt(1))) {
+		return nil, ErrNumberIsOutOfRange
+	}
+
+	pubX, pubY := scalarBaseMult(curve, d)
+
+	return &PrivateKey{
+		PK: PublicKey{Curve: curve, X: pubX, Y: pubY},
+		D:  new(big.Int).Set(d),
+	}, nil
+}
+
 // Sign msg - message to sign, d - privateKey, k - more for test purposes, but also can be generated non programming way
 func Sign(curve elliptic.Curve, msg []byte, d *big.Int, k *big.Int) (*Signature, error) {
-	var err error = nil
+	return SignWithOptions(curve, msg, d, k, nil)
+}
+
+// SignDeterministic the same as Sign, but k is derived from private key and message hash as described in RFC 6979
+func SignDeterministic(curve elliptic.Curve, msg []byte, d *big.Int) (*Signature, error) {
+	return SignWithOptions(curve, msg, d, nil, &Options{Deterministic: true})
+}
+
+// SignWithOptions Sign with additional settings, see Options
+func SignWithOptions(curve elliptic.Curve, msg []byte, d *big.Int, k *big.Int, opts *Options) (*Signature, error) {
 	// H(m)
-	msgHash := sha256.Compute(msg)
-	h := new(big.Int).SetBytes(msgHash[:])
-	h.Mod(h, curve.Params().N)
+	msgHash := hashMessage(opts.hashFunc(curve), msg)
 
-	r := big.NewInt(0)
-	s := big.NewInt(0)
+	return SignDigestWithOptions(curve, msgHash, d, k, opts)
+}
+
+// SignDigest signs already computed message hash, e.g. the one produced elsewhere
+func SignDigest(curve elliptic.Curve, digest []byte, d *big.Int, k *big.Int) (*Signature, error) {
+	return SignDigestWithOptions(curve, digest, d, k, nil)
+}
+
+// SignDigestWithOptions SignDigest with additional settings, see Options
+func SignDigestWithOptions(curve elliptic.Curve, digest []byte, d *big.Int, k *big.Int, opts *Options) (*Signature, error) {
+	var err error = nil
+
+	N := curve.Params().N
+	h := bits2int(digest, N)
+
+	// explicit k can not be replaced, deterministic k is taken from the same HMAC_DRBG on retry
+	userK := k
+
+	var nonces *nonceGenerator
+	if userK == nil && opts != nil && opts.Deterministic {
+		nonces = newNonceGenerator(curve, opts.hashFunc(curve), d, digest)
+	}
 
-	//if r = 0 then go to start.
-	//if s = 0 go to start.
-	for len(r.Bits()) == 0 || len(s.Bits()) == 0 {
+	var r, s *big.Int
+	v := byte(0)
+
+	//if r = 0 or s = 0 then go to start with the next k.
+	for {
 		//k <= random ∈ [1, n - 1].
-		if k == nil {
-			k, err = rand.Int(rand.Reader, curve.Params().N)
+		switch {
+		case userK != nil:
+			k = userK
+		case nonces != nil:
+			k = nonces.next()
+		default:
+			k, err = rand.Int(rand.Reader, N)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to generate random big int")
 			}
 		}
 
-		//k x P = (x1, y1)
-		x, _ := curve.ScalarBaseMult(k.Bytes())
-
-		//r = x1 mod n.
-		r = x.Mod(x, curve.Params().N)
+		//k x P = (x1, y1), computed in constant time, so k does not leak through timing
+		x, y := scalarBaseMult(curve, k)
 
-		if len(r.Bits()) == 0 {
-			continue
+		//recovery id to restore k x P from r
+		v = byte(y.Bit(0))
+		if x.Cmp(N) >= 0 {
+			v |= 2
 		}
 
-		r = new(big.Int).Mod(x, curve.Params().N)
+		//r = x1 mod n.
+		r = x.Mod(x, N)
 
-		//pow(k, -1)
-		kInverse := new(big.Int).ModInverse(k, curve.Params().N)
+		if len(r.Bits()) != 0 {
+			//s = pow(k, -1) * (H(m) + d*r) mod n, also in constant time
+			s = signScalar(curve, k, d, h, r)
 
-		s = new(big.Int).Mul(d, r)
-		s.Add(s, h)
-		s.Mul(s, kInverse)
-		s.Mod(s, curve.Params().N)
+			if len(s.Bits()) != 0 {
+				break
+			}
+		}
 
-		if len(s.Bits()) == 0 {
-			continue
+		if userK != nil {
+			return nil, ErrInvalidNonce
 		}
 	}
 
-	return &Signature{
+	sig := &Signature{
 		S: s,
 		R: r,
-	}, nil
+		V: v,
+	}
+
+	if opts != nil && opts.LowS {
+		sig = sig.Normalize(curve)
+	}
+
+	return sig, nil
 }
 
 func Verify(curve elliptic.Curve, msg []byte, r, s *big.Int, Q PublicKey) (bool, error) {
-	if !curve.IsOnCurve(Q.X, Q.Y) {
-		return false, ErrPublicKeyIsNotOnCurve
+	return VerifyWithOptions(curve, msg, r, s, Q, nil)
+}
+
+// VerifyWithOptions Verify with additional settings, see Options
+func VerifyWithOptions(curve elliptic.Curve, msg []byte, r, s *big.Int, Q PublicKey, opts *Options) (bool, error) {
+	// H(m)
+	msgHash := hashMessage(opts.hashFunc(curve), msg)
+
+	return VerifyDigestWithOptions(curve, msgHash, r, s, Q, opts)
+}
+
+// VerifyDigest verifies signature of already computed message hash
+func VerifyDigest(curve elliptic.Curve, digest []byte, r, s *big.Int, Q PublicKey) (bool, error) {
+	return VerifyDigestWithOptions(curve, digest, r, s, Q, nil)
+}
+
+// VerifyDigestWithOptions VerifyDigest with additional settings, see Options
+func VerifyDigestWithOptions(curve elliptic.Curve, digest []byte, r, s *big.Int, Q PublicKey, opts *Options) (bool, error) {
+	if err := validatePublicKey(curve, Q); err != nil {
+		return false, err
+	}
+
+	if r == nil || s == nil {
+		return false, ErrNilSignatureValue
 	}
 
-	if !checkBigIntInRange(r, big.NewInt(0), curve.Params().N) || !checkBigIntInRange(s, big.NewInt(0), curve.Params().N) {
+	//1 <= r, s <= n - 1
+	maxValue := new(big.Int).Sub(curve.Params().N, big.NewInt(1))
+	if !checkBigIntInRange(r, big.NewInt(1), maxValue) || !checkBigIntInRange(s, big.NewInt(1), maxValue) {
 		return false, ErrNumberIsOutOfRange
 	}
 
-	// H(m)
-	msgHash := sha256.Compute(msg)
-	h := new(big.Int).SetBytes(msgHash[:])
+	if opts != nil && opts.LowS && !IsLowS(curve, s) {
+		return false, ErrHighS
+	}
+
+	h := bits2int(digest, curve.Params().N)
 
 	//pow(s, -1)
 	sInverse := new(big.Int).ModInverse(s, curve.Params().N)
@@ -134,11 +245,56 @@ func Verify(curve elliptic.Curve, msg []byte, r, s *big.Int, Q PublicKey) (bool,
 	//x0,y0 = u*G + v*Q
 	x2, y2 := curve.ScalarBaseMult(u.Bytes())
 	x1, y1 := curve.ScalarMult(Q.X, Q.Y, v.Bytes())
-	x0, _ := curve.Add(x1, y1, x2, y2)
+	x0, y0 := curve.Add(x1, y1, x2, y2)
+
+	// u*G + v*Q is the point at infinity
+	if len(x0.Bits()) == 0 && len(y0.Bits()) == 0 {
+		return false, nil
+	}
+
+	//r == x0 mod n
+	x0.Mod(x0, curve.Params().N)
 
 	return x0.Cmp(r) == 0, nil
 }
 
+// validatePublicKey public key must be a point on curve and not the point at infinity
+func validatePublicKey(curve elliptic.Curve, Q PublicKey) error {
+	if Q.X == nil || Q.Y == nil {
+		return ErrNilPublicKey
+	}
+
+	if len(Q.X.Bits()) == 0 && len(Q.Y.Bits()) == 0 {
+		return ErrZeroPublicKey
+	}
+
+	if !curve.IsOnCurve(Q.X, Q.Y) {
+		return ErrPublicKeyIsNotOnCurve
+	}
+
+	return nil
+}
+
+// hashFunc returns hash with strength matching the curve: SHA256 up to 256-bit curves, SHA384 for P-384 and SHA512 for P-521
+func hashFunc(curve elliptic.Curve) func() hash.Hash {
+	switch bitSize := curve.Params().N.BitLen(); {
+	case bitSize <= 256:
+		return sha256.New
+	case bitSize <= 384:
+		return sha512.New384
+	default:
+		return sha512.New
+	}
+}
+
+func hashMessage(hashFunc func() hash.Hash, msg []byte) []byte {
+	h := hashFunc()
+	h.Write(msg)
+
+	return h.Sum(nil)
+}
+
+// checkBigIntInRange from <= val <= to
 func checkBigIntInRange(val, from, to *big.Int) bool {
-	return !(val.Cmp(from) == -1 && val.Cmp(to) == 1)
+	return val.Cmp(from) >= 0 && val.Cmp(to) <= 0
 }
@@ -1,12 +1,33 @@
 package ecdsa
 
 import (
+	"bytes"
+	stdcrypto "crypto"
+	stdecdsa "crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	stdsha256 "crypto/sha256"
+	stdsha512 "crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"math"
 	"math/big"
+	mathrand "math/rand"
+	"os"
+	"path/filepath"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mhrynenko/cryptography_course/secp256k1"
+	"github.com/mhrynenko/cryptography_course/sha256"
+	"github.com/mhrynenko/cryptography_course/sha512"
 )
 
 type Vector struct {
@@ -84,3 +105,1528 @@ func TestECDSA(t *testing.T) {
 		t.Errorf("ecdsa.Verify: signature is not verified")
 	}
 }
+
+func TestMatchingHash(t *testing.T) {
+	// RFC 6979 A.2.6, P-384 with SHA-384
+	key := PrivateKey{
+		D: new(big.Int).SetBytes(common.Hex2Bytes("6B9D3DAD2E1B8C1C05B19875B6659F4DE23C3B667BF297BA9AA47740787137D896D5724E4C70A825F872C9EA60D2EDF5")),
+		PK: PublicKey{
+			X: new(big.Int).SetBytes(common.Hex2Bytes("EC3A4E415B4E19A4568618029F427FA5DA9A8BC4AE92E02E06AAE5286B300C64DEF8F0EA9055866064A254515480BC13")),
+			Y: new(big.Int).SetBytes(common.Hex2Bytes("8015D9B72D7D57244EA8EF9AC0C621896708A59367F9DFB9F54CA84B3F1C9DB1288B231C3AE0D4FE7344FD2533264720")),
+		},
+	}
+	vector := Vector{
+		curve: elliptic.P384(),
+		m:     "sample",
+		k:     new(big.Int).SetBytes(common.Hex2Bytes("94ED910D1A099DAD3254E9242AE85ABDE4BA15168EAF0CA87A555FD56D10FBCA2907E3E83BA95368623B8C4686915CF9")),
+		r:     new(big.Int).SetBytes(common.Hex2Bytes("94EDBB92A5ECB8AAD4736E56C691916B3F88140666CE9FA73D64C4EA95AD133C81A648152E44ACF96E36DD1E80FABE46")),
+		s:     new(big.Int).SetBytes(common.Hex2Bytes("99EF4AEB15F178CEA1FE40DB2603138F130E740A19624526203B6351D0A3A94FA329C145786E679E7B82C71A38628AC8")),
+	}
+
+	sig, err := Sign(vector.curve, []byte(vector.m), key.D, vector.k)
+	if err != nil {
+		t.Errorf("ecdsa.Sign: unexcpected error `%s` for P-384 vector", err.Error())
+	}
+
+	if sig.S.Cmp(vector.s) != 0 || sig.R.Cmp(vector.r) != 0 {
+		t.Errorf("ecdsa.Sign: signature is not the same for P-384 vector")
+	}
+
+	isVerified, err := Verify(vector.curve, []byte(vector.m), sig.R, sig.S, key.PK)
+	if err != nil || !isVerified {
+		t.Errorf("ecdsa.Verify: signature is not verified for P-384 vector")
+	}
+
+	// P-521 is paired with SHA-512, check it with standard library
+	generated, err := GeneratePrivateKey(elliptic.P521())
+	if err != nil {
+		t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+	}
+
+	msg := []byte("Hello world!")
+	sig, err = Sign(elliptic.P521(), msg, generated.D, nil)
+	if err != nil {
+		t.Fatalf("ecdsa.Sign: unexcpected error `%s`", err.Error())
+	}
+
+	digest := stdsha512.Sum512(msg)
+	pub := &stdecdsa.PublicKey{Curve: elliptic.P521(), X: generated.PK.X, Y: generated.PK.Y}
+	if !stdecdsa.Verify(pub, digest[:], sig.R, sig.S) {
+		t.Errorf("ecdsa.Sign: P-521 signature is not verified by crypto/ecdsa")
+	}
+}
+
+type DeterministicVector struct {
+	Vector
+	d *big.Int
+}
+
+var (
+	// private keys from RFC 6979 appendix A.2
+	rfc6979P224 = new(big.Int).SetBytes(common.Hex2Bytes("F220266E1105BFE3083E03EC7A3A654651F45E37167E88600BF257C1"))
+	rfc6979P256 = new(big.Int).SetBytes(common.Hex2Bytes("C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721"))
+	rfc6979P384 = new(big.Int).SetBytes(common.Hex2Bytes("6B9D3DAD2E1B8C1C05B19875B6659F4DE23C3B667BF297BA9AA47740787137D896D5724E4C70A825F872C9EA60D2EDF5"))
+	rfc6979P521 = new(big.Int).SetBytes(common.Hex2Bytes("00FAD06DAA62BA3B25D2FB40133DA757205DE67F5BB0018FEE8C86E1B68C7E75CAA896EB32F1F47C70855836A6D16FCC1466F6D8FBEC67DB89EC0C08B0E996B83538"))
+)
+
+// deterministicVectors from RFC 6979 appendix A.2 with hash matching the curve (SHA-256 for P-224, so hash is truncated)
+var deterministicVectors = []DeterministicVector{
+	{
+		Vector: Vector{
+			curve: elliptic.P224(),
+			m:     "sample",
+			k:     new(big.Int).SetBytes(common.Hex2Bytes("AD3029E0278F80643DE33917CE6908C70A8FF50A411F06E41DEDFCDC")),
+			r:     new(big.Int).SetBytes(common.Hex2Bytes("61AA3DA010E8E8406C656BC477A7A7189895E7E840CDFE8FF42307BA")),
+			s:     new(big.Int).SetBytes(common.Hex2Bytes("BC814050DAB5D23770879494F9E0A680DC1AF7161991BDE692B10101")),
+		},
+		d: rfc6979P224,
+	},
+	{
+		Vector: Vector{
+			curve: elliptic.P224(),
+			m:     "test",
+			k:     new(big.Int).SetBytes(common.Hex2Bytes("FF86F57924DA248D6E44E8154EB69F0AE2AEBAEE9931D0B5A969F904")),
+			r:     new(big.Int).SetBytes(common.Hex2Bytes("AD04DDE87B84747A243A631EA47A1BA6D1FAA059149AD2440DE6FBA6")),
+			s:     new(big.Int).SetBytes(common.Hex2Bytes("178D49B1AE90E3D8B629BE3DB5683915F4E8C99FDF6E666CF37ADCFD")),
+		},
+		d: rfc6979P224,
+	},
+	{
+		Vector: vectors[0],
+		d:      rfc6979P256,
+	},
+	{
+		Vector: vectors[1],
+		d:      rfc6979P256,
+	},
+	{
+		Vector: Vector{
+			curve: elliptic.P384(),
+			m:     "sample",
+			k:     new(big.Int).SetBytes(common.Hex2Bytes("94ED910D1A099DAD3254E9242AE85ABDE4BA15168EAF0CA87A555FD56D10FBCA2907E3E83BA95368623B8C4686915CF9")),
+			r:     new(big.Int).SetBytes(common.Hex2Bytes("94EDBB92A5ECB8AAD4736E56C691916B3F88140666CE9FA73D64C4EA95AD133C81A648152E44ACF96E36DD1E80FABE46")),
+			s:     new(big.Int).SetBytes(common.Hex2Bytes("99EF4AEB15F178CEA1FE40DB2603138F130E740A19624526203B6351D0A3A94FA329C145786E679E7B82C71A38628AC8")),
+		},
+		d: rfc6979P384,
+	},
+	{
+		Vector: Vector{
+			curve: elliptic.P384(),
+			m:     "test",
+			k:     new(big.Int).SetBytes(common.Hex2Bytes("015EE46A5BF88773ED9123A5AB0807962D193719503C527B031B4C2D225092ADA71F4A459BC0DA98ADB95837DB8312EA")),
+			r:     new(big.Int).SetBytes(common.Hex2Bytes("8203B63D3C853E8D77227FB377BCF7B7B772E97892A80F36AB775D509D7A5FEB0542A7F0812998DA8F1DD3CA3CF023DB")),
+			s:     new(big.Int).SetBytes(common.Hex2Bytes("DDD0760448D42D8A43AF45AF836FCE4DE8BE06B485E9B61B827C2F13173923E06A739F040649A667BF3B828246BAA5A5")),
+		},
+		d: rfc6979P384,
+	},
+	{
+		Vector: Vector{
+			curve: elliptic.P521(),
+			m:     "sample",
+			k:     new(big.Int).SetBytes(common.Hex2Bytes("01DAE2EA071F8110DC26882D4D5EAE0621A3256FC8847FB9022E2B7D28E6F10198B1574FDD03A9053C08A1854A168AA5A57470EC97DD5CE090124EF52A2F7ECBFFD3")),
+			r:     new(big.Int).SetBytes(common.Hex2Bytes("00C328FAFCBD79DD77850370C46325D987CB525569FB63C5D3BC53950E6D4C5F174E25A1EE9017B5D450606ADD152B534931D7D4E8455CC91F9B15BF05EC36E377FA")),
+			s:     new(big.Int).SetBytes(common.Hex2Bytes("00617CCE7CF5064806C467F678D3B4080D6F1CC50AF26CA209417308281B68AF282623EAA63E5B5C0723D8B8C37FF0777B1A20F8CCB1DCCC43997F1EE0E44DA4A67A")),
+		},
+		d: rfc6979P521,
+	},
+	{
+		Vector: Vector{
+			curve: elliptic.P521(),
+			m:     "test",
+			k:     new(big.Int).SetBytes(common.Hex2Bytes("016200813020EC986863BEDFC1B121F605C1215645018AEA1A7B215A564DE9EB1B38A67AA1128B80CE391C4FB71187654AAA3431027BFC7F395766CA988C964DC56D")),
+			r:     new(big.Int).SetBytes(common.Hex2Bytes("013E99020ABF5CEE7525D16B69B229652AB6BDF2AFFCAEF38773B4B7D08725F10CDB93482FDCC54EDCEE91ECA4166B2A7C6265EF0CE2BD7051B7CEF945BABD47EE6D")),
+			s:     new(big.Int).SetBytes(common.Hex2Bytes("01FBD0013C674AA79CB39849527916CE301C66EA7CE8B80682786AD60F98F7E78A19CA69EFF5C57400E3B3A0AD66CE0978214D13BAF4E9AC60752F7B155E2DE4DCE3")),
+		},
+		d: rfc6979P521,
+	},
+}
+
+type NonceVector struct {
+	hash func() hash.Hash
+	m    string
+	k    *big.Int
+}
+
+// nonceVectors full set of nonces from RFC 6979 appendix A.2.5 (P-256)
+var nonceVectors = []NonceVector{
+	{hash: sha1.New, m: "sample", k: new(big.Int).SetBytes(common.Hex2Bytes("882905F1227FD620FBF2ABF21244F0BA83D0DC3A9103DBBEE43A1FB858109DB4"))},
+	{hash: stdsha256.New224, m: "sample", k: new(big.Int).SetBytes(common.Hex2Bytes("103F90EE9DC52E5E7FB5132B7033C63066D194321491862059967C715985D473"))},
+	{hash: stdsha256.New, m: "sample", k: new(big.Int).SetBytes(common.Hex2Bytes("A6E3C57DD01ABE90086538398355DD4C3B17AA873382B0F24D6129493D8AAD60"))},
+	{hash: stdsha512.New384, m: "sample", k: new(big.Int).SetBytes(common.Hex2Bytes("09F634B188CEFD98E7EC88B1AA9852D734D0BC272F7D2A47DECC6EBEB375AAD4"))},
+	{hash: stdsha512.New, m: "sample", k: new(big.Int).SetBytes(common.Hex2Bytes("5FA81C63109BADB88C1F367B47DA606DA28CAD69AA22C4FE6AD7DF73A7173AA5"))},
+	{hash: sha1.New, m: "test", k: new(big.Int).SetBytes(common.Hex2Bytes("8C9520267C55D6B980DF741E56B4ADEE114D84FBFA2E62137954164028632A2E"))},
+	{hash: stdsha256.New224, m: "test", k: new(big.Int).SetBytes(common.Hex2Bytes("669F4426F2688B8BE0DB3A6BD1989BDAEFFF84B649EEB84F3DD26080F667FAA7"))},
+	{hash: stdsha256.New, m: "test", k: new(big.Int).SetBytes(common.Hex2Bytes("D16B6AE827F17175E040871A1C7EC3500192C4C92677336EC2537ACAEE0008E0"))},
+	{hash: stdsha512.New384, m: "test", k: new(big.Int).SetBytes(common.Hex2Bytes("16AEFFA357260B04B1DD199693960740066C1A8F3E8EDD79070AA914D361B3B8"))},
+	{hash: stdsha512.New, m: "test", k: new(big.Int).SetBytes(common.Hex2Bytes("6915D11632ACA3C40D5D51C08DAF9C555933819548784480E93499000D9F0B7F"))},
+}
+
+// TestLegacySHA256 signatures made with SHA256 on P-384 and P-521 (the default before matching hash was used)
+// are rejected by plain Verify, but still can be verified with explicit hash
+func TestLegacySHA256(t *testing.T) {
+	// RFC 6979 A.2.6, P-384 with SHA-256
+	pk := PublicKey{
+		X: new(big.Int).SetBytes(common.Hex2Bytes("EC3A4E415B4E19A4568618029F427FA5DA9A8BC4AE92E02E06AAE5286B300C64DEF8F0EA9055866064A254515480BC13")),
+		Y: new(big.Int).SetBytes(common.Hex2Bytes("8015D9B72D7D57244EA8EF9AC0C621896708A59367F9DFB9F54CA84B3F1C9DB1288B231C3AE0D4FE7344FD2533264720")),
+	}
+	r := new(big.Int).SetBytes(common.Hex2Bytes("21B13D1E013C7FA1392D03C5F99AF8B30C570C6F98D4EA8E354B63A21D3DAA33BDE1E888E63355D92FA2B3C36D8FB2CD"))
+	s := new(big.Int).SetBytes(common.Hex2Bytes("F3AA443FB107745BF4BD77CB3891674632068A10CA67E3D45DB2266FA7D1FEEBEFDC63ECCD1AC42EC0CB8668A4FA0AB0"))
+
+	if isVerified, _ := Verify(elliptic.P384(), []byte("sample"), r, s, pk); isVerified {
+		t.Errorf("ecdsa.Verify: SHA256 signature is verified with SHA384 on P-384")
+	}
+
+	isVerified, err := VerifyWithOptions(elliptic.P384(), []byte("sample"), r, s, pk, &Options{Hash: sha256.New})
+	if err != nil || !isVerified {
+		t.Errorf("ecdsa.VerifyWithOptions: SHA256 signature is not verified on P-384, err = %v", err)
+	}
+
+	// P-521 signature with SHA256 made by standard library
+	lib, err := stdecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey: unexcpected error `%s`", err.Error())
+	}
+
+	msg := []byte("Hello world!")
+	digest := stdsha256.Sum256(msg)
+
+	r, s, err = stdecdsa.Sign(rand.Reader, lib, digest[:])
+	if err != nil {
+		t.Fatalf("ecdsa.Sign: unexcpected error `%s`", err.Error())
+	}
+
+	pk = PublicKey{X: lib.X, Y: lib.Y}
+
+	if isVerified, _ = Verify(elliptic.P521(), msg, r, s, pk); isVerified {
+		t.Errorf("ecdsa.Verify: SHA256 signature is verified with SHA512 on P-521")
+	}
+
+	isVerified, err = VerifyWithOptions(elliptic.P521(), msg, r, s, pk, &Options{Hash: sha256.New})
+	if err != nil || !isVerified {
+		t.Errorf("ecdsa.VerifyWithOptions: SHA256 signature is not verified on P-521, err = %v", err)
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	for i, vector := range deterministicVectors {
+		msg := []byte(vector.m)
+
+		k := generateK(vector.curve, hashFunc(vector.curve), vector.d, hashMessage(hashFunc(vector.curve), msg))
+		if k.Cmp(vector.k) != 0 {
+			t.Errorf("ecdsa.generateK: nonce is not the same for %d vector", i)
+		}
+
+		sig, err := SignDeterministic(vector.curve, msg, vector.d)
+		if err != nil {
+			t.Errorf("ecdsa.SignDeterministic: unexcpected error `%s` for %d vector", err.Error(), i)
+			continue
+		}
+
+		if sig.S.Cmp(vector.s) != 0 || sig.R.Cmp(vector.r) != 0 {
+			t.Errorf("ecdsa.SignDeterministic: signature is not the same for %d vector", i)
+		}
+	}
+
+	for i, vector := range nonceVectors {
+		h := vector.hash()
+		h.Write([]byte(vector.m))
+
+		k := generateK(elliptic.P256(), vector.hash, rfc6979P256, h.Sum(nil))
+		if k.Cmp(vector.k) != 0 {
+			t.Errorf("ecdsa.generateK: nonce is not the same for %d nonce vector", i)
+		}
+	}
+}
+
+// TestZeroSignatureValue s = 0 can not be produced naturally, so d is chosen to make H(m) + d*r = 0 for given k
+func TestZeroSignatureValue(t *testing.T) {
+	curve := elliptic.P256()
+	N := curve.Params().N
+
+	msg := []byte("sample")
+	k := big.NewInt(12345)
+
+	x, _ := curve.ScalarBaseMult(k.Bytes())
+	r := new(big.Int).Mod(x, N)
+
+	//d = -H(m) * r^-1
+	h := bits2int(hashMessage(sha256.New, msg), N)
+	d := new(big.Int).Neg(h)
+	d.Mul(d, new(big.Int).ModInverse(r, N))
+	d.Mod(d, N)
+
+	if _, err := Sign(curve, msg, d, k); err != ErrInvalidNonce {
+		t.Errorf("ecdsa.Sign: expected ErrInvalidNonce for k giving s = 0, err = %v", err)
+	}
+
+	// without explicit k another nonce is used
+	sig, err := Sign(curve, msg, d, nil)
+	if err != nil {
+		t.Fatalf("ecdsa.Sign: unexcpected error `%s`", err.Error())
+	}
+
+	pubX, pubY := curve.ScalarBaseMult(d.Bytes())
+	if isVerified, err := Verify(curve, msg, sig.R, sig.S, PublicKey{X: pubX, Y: pubY}); err != nil || !isVerified {
+		t.Errorf("ecdsa.Verify: signature with replaced nonce is not verified")
+	}
+}
+
+// TestNonceGenerator the first candidate is RFC 6979 k, next ones continue HMAC_DRBG (step h.3) instead of repeating it
+func TestNonceGenerator(t *testing.T) {
+	curve := elliptic.P256()
+	digest := hashMessage(sha256.New, []byte("sample"))
+
+	generator := newNonceGenerator(curve, sha256.New, rfc6979P256, digest)
+
+	first := generator.next()
+	if first.Cmp(generateK(curve, sha256.New, rfc6979P256, digest)) != 0 {
+		t.Errorf("ecdsa.nonceGenerator: first candidate differs from generateK")
+	}
+
+	seen := map[string]bool{first.String(): true}
+	for i := 0; i < 10; i++ {
+		k := generator.next()
+		if seen[k.String()] || k.Sign() <= 0 || k.Cmp(curve.Params().N) >= 0 {
+			t.Fatalf("ecdsa.nonceGenerator: wrong %d candidate", i)
+		}
+
+		seen[k.String()] = true
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	curve := elliptic.P256()
+	msg := []byte("Hello world!")
+
+	sig, err := SignDeterministic(curve, msg, rfc6979P256)
+	if err != nil {
+		t.Fatalf("ecdsa.SignDeterministic: unexcpected error `%s`", err.Error())
+	}
+
+	der, err := sig.MarshalASN1()
+	if err != nil {
+		t.Fatalf("ecdsa.MarshalASN1: unexcpected error `%s`", err.Error())
+	}
+
+	digest := stdsha256.Sum256(msg)
+	pubX, pubY := curve.ScalarBaseMult(rfc6979P256.Bytes())
+	pub := &stdecdsa.PublicKey{Curve: curve, X: pubX, Y: pubY}
+
+	if !stdecdsa.VerifyASN1(pub, digest[:], der) {
+		t.Errorf("ecdsa.MarshalASN1: signature is not verified by crypto/ecdsa")
+	}
+
+	parsed, err := ParseASN1(der)
+	if err != nil {
+		t.Fatalf("ecdsa.ParseASN1: unexcpected error `%s`", err.Error())
+	}
+
+	if parsed.R.Cmp(sig.R) != 0 || parsed.S.Cmp(sig.S) != 0 {
+		t.Errorf("ecdsa.ParseASN1: signature is not the same after round trip")
+	}
+
+	raw, err := sig.MarshalRaw(curve)
+	if err != nil {
+		t.Fatalf("ecdsa.MarshalRaw: unexcpected error `%s`", err.Error())
+	}
+
+	if len(raw) != 64 {
+		t.Errorf("ecdsa.MarshalRaw: wrong length %d", len(raw))
+	}
+
+	parsed, err = ParseRaw(curve, raw)
+	if err != nil {
+		t.Fatalf("ecdsa.ParseRaw: unexcpected error `%s`", err.Error())
+	}
+
+	if parsed.R.Cmp(sig.R) != 0 || parsed.S.Cmp(sig.S) != 0 {
+		t.Errorf("ecdsa.ParseRaw: signature is not the same after round trip")
+	}
+
+	if _, err = ParseRaw(curve, raw[1:]); err == nil {
+		t.Errorf("ecdsa.ParseRaw: short signature is accepted")
+	}
+
+	invalid := map[string]string{
+		"trailing data":              "3006020101020101" + "00",
+		"non-minimal integer":        "300702020001020101",
+		"negative integer":           "3006020180020101",
+		"non-minimal length":         "30810602010102010101",
+		"indefinite length":          "3080020101020101" + "0000",
+		"wrong tag":                  "3106020101020101",
+		"truncated":                  "30060201010201",
+		"extra element":              "3009020101020101020101",
+		"length longer than content": "3007020101020101",
+	}
+
+	for name, encoded := range invalid {
+		if _, err = ParseASN1(common.Hex2Bytes(encoded)); err == nil {
+			t.Errorf("ecdsa.ParseASN1: invalid encoding with %s is accepted", name)
+		}
+	}
+
+	if _, err = ParseASN1(common.Hex2Bytes("3006020101020101")); err != nil {
+		t.Errorf("ecdsa.ParseASN1: unexcpected error `%s` for minimal signature", err.Error())
+	}
+}
+
+func TestKeySerialization(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		name := curve.Params().Name
+
+		key, err := GeneratePrivateKey(curve)
+		if err != nil {
+			t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		for _, encoded := range [][]byte{key.PK.Marshal(curve), key.PK.MarshalCompressed(curve)} {
+			pk, err := ParsePublicKey(curve, encoded)
+			if err != nil {
+				t.Errorf("ecdsa.ParsePublicKey: unexcpected error `%s` for %s", err.Error(), name)
+				continue
+			}
+
+			if pk.X.Cmp(key.PK.X) != 0 || pk.Y.Cmp(key.PK.Y) != 0 {
+				t.Errorf("ecdsa.ParsePublicKey: public key is not the same for %s", name)
+			}
+		}
+
+		sec1, err := MarshalSEC1PrivateKey(curve, key)
+		if err != nil {
+			t.Fatalf("ecdsa.MarshalSEC1PrivateKey: unexcpected error `%s` for %s", err.Error(), name)
+		}
+
+		lib, err := x509.ParseECPrivateKey(sec1)
+		if err != nil || lib.D.Cmp(key.D) != 0 || lib.Curve != curve {
+			t.Errorf("ecdsa.MarshalSEC1PrivateKey: key is not parsed by crypto/x509 for %s", name)
+		}
+
+		pkcs8, err := MarshalPKCS8PrivateKey(curve, key)
+		if err != nil {
+			t.Fatalf("ecdsa.MarshalPKCS8PrivateKey: unexcpected error `%s` for %s", err.Error(), name)
+		}
+
+		libPKCS8, err := x509.MarshalPKCS8PrivateKey(lib)
+		if err != nil || !bytes.Equal(pkcs8, libPKCS8) {
+			t.Errorf("ecdsa.MarshalPKCS8PrivateKey: encoding differs from crypto/x509 for %s", name)
+		}
+
+		pkix, err := MarshalPKIXPublicKey(curve, &key.PK)
+		if err != nil {
+			t.Fatalf("ecdsa.MarshalPKIXPublicKey: unexcpected error `%s` for %s", err.Error(), name)
+		}
+
+		libPKIX, err := x509.MarshalPKIXPublicKey(&lib.PublicKey)
+		if err != nil || !bytes.Equal(pkix, libPKIX) {
+			t.Errorf("ecdsa.MarshalPKIXPublicKey: encoding differs from crypto/x509 for %s", name)
+		}
+
+		for _, marshal := range []func(elliptic.Curve, *PrivateKey) ([]byte, error){MarshalPrivateKeyPEM, MarshalPKCS8PrivateKeyPEM} {
+			encoded, err := marshal(curve, key)
+			if err != nil {
+				t.Fatalf("ecdsa.Marshal: unexcpected error `%s` for %s", err.Error(), name)
+			}
+
+			parsedCurve, parsed, err := ParsePrivateKeyPEM(encoded)
+			if err != nil {
+				t.Fatalf("ecdsa.ParsePrivateKeyPEM: unexcpected error `%s` for %s", err.Error(), name)
+			}
+
+			if parsedCurve != curve || parsed.D.Cmp(key.D) != 0 || parsed.PK.X.Cmp(key.PK.X) != 0 || parsed.PK.Y.Cmp(key.PK.Y) != 0 {
+				t.Errorf("ecdsa.ParsePrivateKeyPEM: key is not the same for %s", name)
+			}
+		}
+
+		encoded, err := MarshalPublicKeyPEM(curve, &key.PK)
+		if err != nil {
+			t.Fatalf("ecdsa.MarshalPublicKeyPEM: unexcpected error `%s` for %s", err.Error(), name)
+		}
+
+		parsedCurve, pk, err := ParsePublicKeyPEM(encoded)
+		if err != nil || parsedCurve != curve || pk.X.Cmp(key.PK.X) != 0 || pk.Y.Cmp(key.PK.Y) != 0 {
+			t.Errorf("ecdsa.ParsePublicKeyPEM: public key is not the same for %s", name)
+		}
+	}
+}
+
+func TestOpenSSLKeys(t *testing.T) {
+	files := []struct {
+		private string
+		public  []string
+		curve   elliptic.Curve
+	}{
+		{private: "testdata/p256.pem", public: []string{"testdata/p256_pub.pem", "testdata/p256_pub_compressed.pem"}, curve: elliptic.P256()},
+		{private: "testdata/p384_pkcs8.pem", public: []string{"testdata/p384_pub.pem"}, curve: elliptic.P384()},
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file.private)
+		if err != nil {
+			t.Fatalf("failed to read `%s`: %s", file.private, err.Error())
+		}
+
+		curve, key, err := ParsePrivateKeyPEM(data)
+		if err != nil {
+			t.Fatalf("ecdsa.ParsePrivateKeyPEM: unexcpected error `%s` for `%s`", err.Error(), file.private)
+		}
+
+		if curve != file.curve {
+			t.Errorf("ecdsa.ParsePrivateKeyPEM: wrong curve for `%s`", file.private)
+		}
+
+		for _, public := range file.public {
+			data, err = os.ReadFile(public)
+			if err != nil {
+				t.Fatalf("failed to read `%s`: %s", public, err.Error())
+			}
+
+			curve, pk, err := ParsePublicKeyPEM(data)
+			if err != nil {
+				t.Fatalf("ecdsa.ParsePublicKeyPEM: unexcpected error `%s` for `%s`", err.Error(), public)
+			}
+
+			if curve != file.curve || pk.X.Cmp(key.PK.X) != 0 || pk.Y.Cmp(key.PK.Y) != 0 {
+				t.Errorf("ecdsa.ParsePublicKeyPEM: public key from `%s` does not match private key", public)
+			}
+		}
+
+		sig, err := SignDeterministic(curve, []byte("Hello world!"), key.D)
+		if err != nil {
+			t.Fatalf("ecdsa.SignDeterministic: unexcpected error `%s`", err.Error())
+		}
+
+		isVerified, err := Verify(curve, []byte("Hello world!"), sig.R, sig.S, key.PK)
+		if err != nil || !isVerified {
+			t.Errorf("ecdsa.Verify: signature is not verified with key from `%s`", file.private)
+		}
+	}
+
+	if _, _, err := ParsePrivateKeyPEM([]byte("not a pem")); err == nil {
+		t.Errorf("ecdsa.ParsePrivateKeyPEM: invalid data is accepted")
+	}
+
+	if _, err := MarshalSEC1PrivateKey(elliptic.P256(), &PrivateKey{}); err != ErrNilPrivateKey {
+		t.Errorf("ecdsa.MarshalSEC1PrivateKey: wrong error for nil private key, err = %v", err)
+	}
+}
+
+func TestPointCompression(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521(), crypto.S256()} {
+		name := curve.Params().Name
+		P := curve.Params().P
+
+		key, err := GeneratePrivateKey(curve)
+		if err != nil {
+			t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		// -Q = (x, p - y) has another parity of Y, so both prefixes are checked
+		negated := PublicKey{X: key.PK.X, Y: new(big.Int).Sub(P, key.PK.Y)}
+
+		for _, pk := range []PublicKey{key.PK, negated} {
+			compressed := pk.MarshalCompressed(curve)
+			if compressed[0] != byte(2+pk.Y.Bit(0)) {
+				t.Errorf("ecdsa.MarshalCompressed: wrong prefix for %s", name)
+			}
+
+			parsed, err := ParsePublicKey(curve, compressed)
+			if err != nil {
+				t.Errorf("ecdsa.ParsePublicKey: unexcpected error `%s` for %s", err.Error(), name)
+				continue
+			}
+
+			if parsed.X.Cmp(pk.X) != 0 || parsed.Y.Cmp(pk.Y) != 0 {
+				t.Errorf("ecdsa.ParsePublicKey: decompressed point is not the same for %s", name)
+			}
+		}
+
+		// look for X, where x^3 + a*x + b is not a square, so there is no point with such X
+		x := big.NewInt(1)
+		for ; ; x.Add(x, big.NewInt(1)) {
+			if _, err = decompressPoint(curve, x, false); err != nil {
+				break
+			}
+		}
+
+		notOnCurve := (&PublicKey{X: x, Y: big.NewInt(0)}).MarshalCompressed(curve)
+		if _, err = ParsePublicKey(curve, notOnCurve); err != ErrPublicKeyIsNotOnCurve {
+			t.Errorf("ecdsa.ParsePublicKey: X not on curve is accepted for %s", name)
+		}
+
+		tooBig := (&PublicKey{X: P, Y: big.NewInt(0)}).MarshalCompressed(curve)
+		if _, err = ParsePublicKey(curve, tooBig); err == nil {
+			t.Errorf("ecdsa.ParsePublicKey: X bigger than field is accepted for %s", name)
+		}
+	}
+}
+
+// TestModSqrt in-house square root is compared with big.Int.ModSqrt, P-224 goes through Tonelli-Shanks
+func TestModSqrt(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521(), crypto.S256()} {
+		name := curve.Params().Name
+		P := curve.Params().P
+
+		for i := 0; i < 50; i++ {
+			a, _ := rand.Int(rand.Reader, P)
+
+			local := modSqrt(a, P)
+			lib := new(big.Int).ModSqrt(a, P)
+
+			if (local == nil) != (lib == nil) {
+				t.Errorf("ecdsa.modSqrt: wrong residuosity for %s", name)
+				continue
+			}
+
+			if local == nil {
+				continue
+			}
+
+			// roots are y and p - y, so only square is compared
+			square := new(big.Int).Mul(local, local)
+			if square.Mod(square, P).Cmp(a) != 0 {
+				t.Errorf("ecdsa.modSqrt: wrong root for %s", name)
+			}
+		}
+	}
+}
+
+type Secp256k1Vector struct {
+	d string
+	m string
+	k string
+	r string
+	s string
+}
+
+// secp256k1Vectors deterministic (RFC 6979, SHA-256) signatures used by Bitcoin libraries, s is low (<= n/2)
+var secp256k1Vectors = []Secp256k1Vector{
+	{
+		d: "0000000000000000000000000000000000000000000000000000000000000001",
+		m: "Everything should be made as simple as possible, but not simpler.",
+		k: "ec633bd56a5774a0940cb97e27a9e4e51dc94af737596a0c5cbb3d30332d92a5",
+		r: "33a69cd2065432a30f3d1ce4eb0d59b8ab58c74f27c41a7fdb5696ad4e6108c9",
+		s: "6f807982866f785d3f6418d24163ddae117b7db4d5fdf0071de069fa54342262",
+	},
+	{
+		d: "0000000000000000000000000000000000000000000000000000000000000001",
+		m: "Satoshi Nakamoto",
+		k: "8f8a276c19f4149656b280621e358cce24f5f52542772691ee69063b74f15d15",
+		r: "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8",
+		s: "2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5",
+	},
+	{
+		d: "f8b8af8ce3c7cca5e300d33939540c10d45ce001b8f252bfbc57ba0342904181",
+		m: "Alan Turing",
+		r: "7063ae83e7f62bbb171798131b4a0564b956930092b33b07b395615d9ec7e15c",
+		s: "58dfcc1e00a35e1572f366ffe34ba0fc47db1e7189759b9fb233c5b05ab388ea",
+	},
+	{
+		d: "e91671c46231f833a6406ccbea0e3e392c76c167bac1cb013f6f1013980455c2",
+		m: "There is a computer disease that anybody who works with computers knows about. It's a very serious disease and it interferes completely with the work. The trouble with computers is that you 'play' with them!",
+		r: "b552edd27580141f3b2a5463048cb7cd3e047b97c9f98076c32dbdf85a68718b",
+		s: "279fa72dd19bfae05577e06c7c0c1900c371fcd5893f7e1d56a37d30174671f6",
+	},
+}
+
+func TestSecp256k1(t *testing.T) {
+	curve := secp256k1.S256()
+	N := curve.Params().N
+
+	for i, vector := range secp256k1Vectors {
+		d := new(big.Int).SetBytes(common.Hex2Bytes(vector.d))
+		msg := []byte(vector.m)
+
+		if vector.k != "" {
+			k := generateK(curve, hashFunc(curve), d, hashMessage(hashFunc(curve), msg))
+			if k.Cmp(new(big.Int).SetBytes(common.Hex2Bytes(vector.k))) != 0 {
+				t.Errorf("ecdsa.generateK: nonce is not the same for %d secp256k1 vector", i)
+			}
+		}
+
+		sig, err := SignDeterministic(curve, msg, d)
+		if err != nil {
+			t.Errorf("ecdsa.SignDeterministic: unexcpected error `%s` for %d secp256k1 vector", err.Error(), i)
+			continue
+		}
+
+		// vectors have low s, so n - s is also accepted
+		s := new(big.Int).SetBytes(common.Hex2Bytes(vector.s))
+		if sig.R.Cmp(new(big.Int).SetBytes(common.Hex2Bytes(vector.r))) != 0 || (sig.S.Cmp(s) != 0 && new(big.Int).Sub(N, sig.S).Cmp(s) != 0) {
+			t.Errorf("ecdsa.SignDeterministic: signature is not the same for %d secp256k1 vector", i)
+		}
+
+		pubX, pubY := curve.ScalarBaseMult(d.Bytes())
+		isVerified, err := Verify(curve, msg, sig.R, sig.S, PublicKey{X: pubX, Y: pubY})
+		if err != nil || !isVerified {
+			t.Errorf("ecdsa.Verify: signature is not verified for %d secp256k1 vector", i)
+		}
+	}
+
+	// signatures must be the same as go-ethereum ones (up to s normalization) and accepted by it
+	for i := 0; i < 10; i++ {
+		key, err := GeneratePrivateKey(curve)
+		if err != nil {
+			t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		msg := []byte(fmt.Sprintf("Hello world! %d", i))
+		digest := stdsha256.Sum256(msg)
+
+		libKey, err := crypto.ToECDSA(key.D.FillBytes(make([]byte, 32)))
+		if err != nil {
+			t.Fatalf("crypto.ToECDSA: unexcpected error `%s`", err.Error())
+		}
+
+		libSig, err := crypto.Sign(digest[:], libKey)
+		if err != nil {
+			t.Fatalf("crypto.Sign: unexcpected error `%s`", err.Error())
+		}
+
+		sig, err := SignDeterministic(curve, msg, key.D)
+		if err != nil {
+			t.Fatalf("ecdsa.SignDeterministic: unexcpected error `%s`", err.Error())
+		}
+
+		if sig.S.Cmp(new(big.Int).Rsh(N, 1)) > 0 {
+			sig.S.Sub(N, sig.S)
+		}
+
+		raw, _ := sig.MarshalRaw(curve)
+		if !bytes.Equal(raw, libSig[:64]) {
+			t.Errorf("ecdsa.SignDeterministic: signature differs from go-ethereum for %d", i)
+		}
+
+		if !crypto.VerifySignature(key.PK.Marshal(curve), digest[:], raw) {
+			t.Errorf("ecdsa.SignDeterministic: signature is not verified by go-ethereum for %d", i)
+		}
+
+		libS := new(big.Int).SetBytes(libSig[32:64])
+		isVerified, err := Verify(curve, msg, new(big.Int).SetBytes(libSig[:32]), libS, key.PK)
+		if err != nil || !isVerified {
+			t.Errorf("ecdsa.Verify: go-ethereum signature is not verified for %d", i)
+		}
+	}
+}
+
+func TestRecoverPublicKey(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521(), secp256k1.S256()} {
+		key, err := GeneratePrivateKey(curve)
+		if err != nil {
+			t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		for i := 0; i < 4; i++ {
+			msg := []byte(fmt.Sprintf("Hello world! %d", i))
+
+			sig, err := Sign(curve, msg, key.D, nil)
+			if err != nil {
+				t.Fatalf("ecdsa.Sign: unexcpected error `%s`", err.Error())
+			}
+
+			pk, err := RecoverPublicKey(curve, msg, sig)
+			if err != nil {
+				t.Errorf("ecdsa.RecoverPublicKey: unexcpected error `%s` for %s", err.Error(), curve.Params().Name)
+				continue
+			}
+
+			if pk.X.Cmp(key.PK.X) != 0 || pk.Y.Cmp(key.PK.Y) != 0 {
+				t.Errorf("ecdsa.RecoverPublicKey: wrong public key for %s", curve.Params().Name)
+			}
+
+			// another recovery id gives another key
+			sig.V ^= 1
+			if pk, err = RecoverPublicKey(curve, msg, sig); err == nil && pk.X.Cmp(key.PK.X) == 0 && pk.Y.Cmp(key.PK.Y) == 0 {
+				t.Errorf("ecdsa.RecoverPublicKey: the same public key with wrong recovery id for %s", curve.Params().Name)
+			}
+		}
+	}
+
+	// compare with go-ethereum, it takes signature as [R || S || V]
+	curve := secp256k1.S256()
+	for i := 0; i < 10; i++ {
+		key, err := GeneratePrivateKey(curve)
+		if err != nil {
+			t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		msg := []byte(fmt.Sprintf("Hello world! %d", i))
+		digest := stdsha256.Sum256(msg)
+
+		sig, err := SignDeterministic(curve, msg, key.D)
+		if err != nil {
+			t.Fatalf("ecdsa.SignDeterministic: unexcpected error `%s`", err.Error())
+		}
+
+		raw, _ := sig.MarshalRaw(curve)
+		libPK, err := crypto.Ecrecover(digest[:], append(raw, sig.V))
+		if err != nil {
+			t.Errorf("crypto.Ecrecover: unexcpected error `%s` for %d", err.Error(), i)
+			continue
+		}
+
+		pk, err := RecoverPublicKey(curve, msg, sig)
+		if err != nil {
+			t.Errorf("ecdsa.RecoverPublicKey: unexcpected error `%s` for %d", err.Error(), i)
+			continue
+		}
+
+		if !bytes.Equal(pk.Marshal(curve), libPK) || !bytes.Equal(key.PK.Marshal(curve), libPK) {
+			t.Errorf("ecdsa.RecoverPublicKey: public key differs from go-ethereum for %d", i)
+		}
+
+		libKey, _ := crypto.ToECDSA(key.D.FillBytes(make([]byte, 32)))
+		libSig, _ := crypto.Sign(digest[:], libKey)
+
+		pk, err = RecoverPublicKey(curve, msg, &Signature{
+			R: new(big.Int).SetBytes(libSig[:32]),
+			S: new(big.Int).SetBytes(libSig[32:64]),
+			V: libSig[64],
+		})
+		if err != nil || !bytes.Equal(pk.Marshal(curve), libPK) {
+			t.Errorf("ecdsa.RecoverPublicKey: public key is not recovered from go-ethereum signature for %d", i)
+		}
+	}
+
+	if _, err := RecoverPublicKey(curve, []byte("msg"), &Signature{R: big.NewInt(1), S: big.NewInt(1), V: 4}); err != ErrInvalidRecoveryID {
+		t.Errorf("ecdsa.RecoverPublicKey: invalid recovery id is accepted")
+	}
+}
+
+func TestLowS(t *testing.T) {
+	curve := secp256k1.S256()
+	N := curve.Params().N
+	opts := &Options{LowS: true}
+
+	key, err := GeneratePrivateKey(curve)
+	if err != nil {
+		t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+	}
+
+	for i := 0; i < 20; i++ {
+		msg := []byte(fmt.Sprintf("Hello world! %d", i))
+
+		sig, err := SignWithOptions(curve, msg, key.D, nil, opts)
+		if err != nil {
+			t.Fatalf("ecdsa.SignWithOptions: unexcpected error `%s`", err.Error())
+		}
+
+		if !IsLowS(curve, sig.S) {
+			t.Errorf("ecdsa.SignWithOptions: signature has high s for %d", i)
+		}
+
+		isVerified, err := VerifyWithOptions(curve, msg, sig.R, sig.S, key.PK, opts)
+		if err != nil || !isVerified {
+			t.Errorf("ecdsa.VerifyWithOptions: low s signature is not verified for %d", i)
+		}
+
+		// flipped s is still valid for plain Verify, but must be rejected in strict mode
+		flipped := new(big.Int).Sub(N, sig.S)
+
+		isVerified, err = Verify(curve, msg, sig.R, flipped, key.PK)
+		if err != nil || !isVerified {
+			t.Errorf("ecdsa.Verify: flipped signature is not verified for %d", i)
+		}
+
+		isVerified, err = VerifyWithOptions(curve, msg, sig.R, flipped, key.PK, opts)
+		if err != ErrHighS || isVerified {
+			t.Errorf("ecdsa.VerifyWithOptions: high s signature is accepted for %d", i)
+		}
+
+		normalized := (&Signature{R: sig.R, S: flipped, V: sig.V ^ 1}).Normalize(curve)
+		if normalized.S.Cmp(sig.S) != 0 || normalized.V != sig.V {
+			t.Errorf("ecdsa.Normalize: signature is not normalized for %d", i)
+		}
+
+		// recovery id must stay valid after normalization
+		pk, err := RecoverPublicKey(curve, msg, normalized)
+		if err != nil || pk.X.Cmp(key.PK.X) != 0 || pk.Y.Cmp(key.PK.Y) != 0 {
+			t.Errorf("ecdsa.Normalize: public key is not recovered after normalization for %d", i)
+		}
+	}
+}
+
+func TestStdlibInterop(t *testing.T) {
+	hashes := map[elliptic.Curve]func([]byte) []byte{
+		elliptic.P224(): func(msg []byte) []byte { h := stdsha256.Sum256(msg); return h[:] },
+		elliptic.P256(): func(msg []byte) []byte { h := stdsha256.Sum256(msg); return h[:] },
+		elliptic.P384(): func(msg []byte) []byte { h := stdsha512.Sum384(msg); return h[:] },
+		elliptic.P521(): func(msg []byte) []byte { h := stdsha512.Sum512(msg); return h[:] },
+	}
+
+	for curve, hashMsg := range hashes {
+		name := curve.Params().Name
+
+		key, err := GeneratePrivateKey(curve)
+		if err != nil {
+			t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		lib := &stdecdsa.PrivateKey{PublicKey: stdecdsa.PublicKey{Curve: curve, X: key.PK.X, Y: key.PK.Y}, D: key.D}
+
+		for i := 0; i < 5; i++ {
+			msg := []byte(fmt.Sprintf("Hello world! %d", i))
+
+			sig, err := Sign(curve, msg, key.D, nil)
+			if err != nil {
+				t.Fatalf("ecdsa.Sign: unexcpected error `%s`", err.Error())
+			}
+
+			if !stdecdsa.Verify(&lib.PublicKey, hashMsg(msg), sig.R, sig.S) {
+				t.Errorf("ecdsa.Sign: signature is not verified by crypto/ecdsa for %s", name)
+			}
+
+			r, s, err := stdecdsa.Sign(rand.Reader, lib, hashMsg(msg))
+			if err != nil {
+				t.Fatalf("crypto/ecdsa.Sign: unexcpected error `%s`", err.Error())
+			}
+
+			isVerified, err := Verify(curve, msg, r, s, key.PK)
+			if err != nil || !isVerified {
+				t.Errorf("ecdsa.Verify: crypto/ecdsa signature is not verified for %s", name)
+			}
+		}
+	}
+}
+
+func TestPluggableHash(t *testing.T) {
+	curve := elliptic.P256()
+	pub := &stdecdsa.PublicKey{Curve: curve}
+	pub.X, pub.Y = curve.ScalarBaseMult(rfc6979P256.Bytes())
+	pk := PublicKey{X: pub.X, Y: pub.Y}
+
+	// all RFC 6979 A.2.5 pairings: nonce is checked through r = (k x P).x
+	for i, vector := range nonceVectors {
+		msg := []byte(vector.m)
+		opts := &Options{Hash: vector.hash, Deterministic: true}
+
+		sig, err := SignWithOptions(curve, msg, rfc6979P256, nil, opts)
+		if err != nil {
+			t.Fatalf("ecdsa.SignWithOptions: unexcpected error `%s`", err.Error())
+		}
+
+		expectedR, _ := curve.ScalarBaseMult(vector.k.Bytes())
+		if sig.R.Cmp(expectedR) != 0 {
+			t.Errorf("ecdsa.SignWithOptions: wrong nonce for %d nonce vector", i)
+		}
+
+		h := vector.hash()
+		h.Write(msg)
+		digest := h.Sum(nil)
+
+		if !stdecdsa.Verify(pub, digest, sig.R, sig.S) {
+			t.Errorf("ecdsa.SignWithOptions: signature is not verified by crypto/ecdsa for %d nonce vector", i)
+		}
+
+		isVerified, err := VerifyWithOptions(curve, msg, sig.R, sig.S, pk, opts)
+		if err != nil || !isVerified {
+			t.Errorf("ecdsa.VerifyWithOptions: signature is not verified for %d nonce vector", i)
+		}
+
+		isVerified, err = VerifyDigest(curve, digest, sig.R, sig.S, pk)
+		if err != nil || !isVerified {
+			t.Errorf("ecdsa.VerifyDigest: signature is not verified for %d nonce vector", i)
+		}
+
+		digestSig, err := SignDigestWithOptions(curve, digest, rfc6979P256, nil, opts)
+		if err != nil || digestSig.R.Cmp(sig.R) != 0 || digestSig.S.Cmp(sig.S) != 0 {
+			t.Errorf("ecdsa.SignDigestWithOptions: signature differs from SignWithOptions for %d nonce vector", i)
+		}
+	}
+
+	// JOSE pairings (ES256, ES384, ES512) with in-repo hashes
+	pairings := []struct {
+		curve elliptic.Curve
+		hash  func() hash.Hash
+		lib   func() hash.Hash
+	}{
+		{curve: elliptic.P256(), hash: sha256.New, lib: stdsha256.New},
+		{curve: elliptic.P384(), hash: sha512.New384, lib: stdsha512.New384},
+		{curve: elliptic.P521(), hash: sha512.New, lib: stdsha512.New},
+		{curve: elliptic.P384(), hash: sha256.New, lib: stdsha256.New},
+	}
+
+	for _, pairing := range pairings {
+		key, err := GeneratePrivateKey(pairing.curve)
+		if err != nil {
+			t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		msg := []byte("Hello world!")
+		lib := pairing.lib()
+		lib.Write(msg)
+		digest := lib.Sum(nil)
+
+		sig, err := SignDigest(pairing.curve, digest, key.D, nil)
+		if err != nil {
+			t.Fatalf("ecdsa.SignDigest: unexcpected error `%s`", err.Error())
+		}
+
+		isVerified, err := VerifyWithOptions(pairing.curve, msg, sig.R, sig.S, key.PK, &Options{Hash: pairing.hash})
+		if err != nil || !isVerified {
+			t.Errorf("ecdsa.VerifyWithOptions: signature is not verified for %s", pairing.curve.Params().Name)
+		}
+
+		libPub := &stdecdsa.PublicKey{Curve: pairing.curve, X: key.PK.X, Y: key.PK.Y}
+		if !stdecdsa.Verify(libPub, digest, sig.R, sig.S) {
+			t.Errorf("ecdsa.SignDigest: signature is not verified by crypto/ecdsa for %s", pairing.curve.Params().Name)
+		}
+	}
+}
+
+func TestSigner(t *testing.T) {
+	key, err := GeneratePrivateKey(elliptic.P256())
+	if err != nil {
+		t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+	}
+
+	var signer stdcrypto.Signer = key
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cryptography course"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, signer.Public(), signer)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate: unexcpected error `%s`", err.Error())
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate: unexcpected error `%s`", err.Error())
+	}
+
+	if err = cert.CheckSignatureFrom(cert); err != nil {
+		t.Errorf("ecdsa.PrivateKey.Sign: certificate signature is not verified: %s", err.Error())
+	}
+
+	certKey, ok := cert.PublicKey.(*stdecdsa.PublicKey)
+	if !ok || certKey.X.Cmp(key.PK.X) != 0 || certKey.Y.Cmp(key.PK.Y) != 0 {
+		t.Errorf("ecdsa.PrivateKey.Public: certificate has wrong public key")
+	}
+
+	digest := stdsha512.Sum384([]byte("Hello world!"))
+	if _, err = key.Sign(rand.Reader, digest[:], stdcrypto.SHA256); err != ErrInvalidDigestSize {
+		t.Errorf("ecdsa.PrivateKey.Sign: digest of wrong size is accepted")
+	}
+
+	// conversion must be lossless in both directions
+	for _, curve := range []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		lib, err := stdecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			t.Fatalf("crypto/ecdsa.GenerateKey: unexcpected error `%s`", err.Error())
+		}
+
+		converted := FromECDSA(lib)
+		if !converted.ToECDSA().Equal(lib) || !FromECDSAPublicKey(&lib.PublicKey).ToECDSA().Equal(&lib.PublicKey) {
+			t.Errorf("ecdsa.FromECDSA: key is changed after conversion for %s", curve.Params().Name)
+		}
+
+		msg := []byte("Hello world!")
+		sig, err := Sign(converted.PK.Curve, msg, converted.D, nil)
+		if err != nil {
+			t.Fatalf("ecdsa.Sign: unexcpected error `%s`", err.Error())
+		}
+
+		if !stdecdsa.Verify(&lib.PublicKey, hashMessage(hashFunc(curve), msg), sig.R, sig.S) {
+			t.Errorf("ecdsa.FromECDSA: signature is not verified by crypto/ecdsa for %s", curve.Params().Name)
+		}
+	}
+}
+
+type wycheproofTest struct {
+	TcId    int      `json:"tcId"`
+	Comment string   `json:"comment"`
+	Msg     string   `json:"msg"`
+	Sig     string   `json:"sig"`
+	Result  string   `json:"result"`
+	Flags   []string `json:"flags"`
+}
+
+type wycheproofGroup struct {
+	Key struct {
+		Curve string `json:"curve"`
+		Wx    string `json:"wx"`
+		Wy    string `json:"wy"`
+	} `json:"key"`
+	Sha   string           `json:"sha"`
+	Tests []wycheproofTest `json:"tests"`
+}
+
+type wycheproofFile struct {
+	Algorithm  string            `json:"algorithm"`
+	TestGroups []wycheproofGroup `json:"testGroups"`
+}
+
+// TestWycheproof negative cases in the format of Project Wycheproof ECDSA test vectors
+func TestWycheproof(t *testing.T) {
+	curves := map[string]elliptic.Curve{
+		"secp256r1": elliptic.P256(),
+		"secp256k1": secp256k1.S256(),
+	}
+
+	files, err := filepath.Glob("testdata/wycheproof/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no wycheproof test files found")
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read `%s`: %s", file, err.Error())
+		}
+
+		var vectors wycheproofFile
+		if err = json.Unmarshal(data, &vectors); err != nil {
+			t.Fatalf("failed to parse `%s`: %s", file, err.Error())
+		}
+
+		for _, group := range vectors.TestGroups {
+			curve, ok := curves[group.Key.Curve]
+			if !ok || group.Sha != "SHA-256" {
+				t.Fatalf("unsupported test group in `%s`", file)
+			}
+
+			Q := PublicKey{
+				Curve: curve,
+				X:     new(big.Int).SetBytes(common.Hex2Bytes(group.Key.Wx)),
+				Y:     new(big.Int).SetBytes(common.Hex2Bytes(group.Key.Wy)),
+			}
+
+			for _, test := range group.Tests {
+				isVerified := false
+
+				sig, err := ParseASN1(common.Hex2Bytes(test.Sig))
+				if err == nil {
+					isVerified, err = Verify(curve, common.Hex2Bytes(test.Msg), sig.R, sig.S, Q)
+				}
+
+				if isVerified != (test.Result == "valid") {
+					t.Errorf("ecdsa.Verify: `%s` test %d (%s) expected to be %s, err = %v", file, test.TcId, test.Comment, test.Result, err)
+				}
+
+				if isVerified && err != nil {
+					t.Errorf("ecdsa.Verify: `%s` test %d (%s) is verified with error `%s`", file, test.TcId, test.Comment, err.Error())
+				}
+			}
+		}
+	}
+}
+
+func TestVerifyInputValidation(t *testing.T) {
+	curve := elliptic.P256()
+	N := curve.Params().N
+	msg := []byte("Hello world!")
+
+	key, err := GeneratePrivateKey(curve)
+	if err != nil {
+		t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+	}
+
+	sig, err := Sign(curve, msg, key.D, nil)
+	if err != nil {
+		t.Fatalf("ecdsa.Sign: unexcpected error `%s`", err.Error())
+	}
+
+	cases := []struct {
+		name string
+		r, s *big.Int
+		Q    PublicKey
+		err  error
+	}{
+		{name: "nil r", r: nil, s: sig.S, Q: key.PK, err: ErrNilSignatureValue},
+		{name: "nil s", r: sig.R, s: nil, Q: key.PK, err: ErrNilSignatureValue},
+		{name: "zero r", r: big.NewInt(0), s: sig.S, Q: key.PK, err: ErrNumberIsOutOfRange},
+		{name: "zero s", r: sig.R, s: big.NewInt(0), Q: key.PK, err: ErrNumberIsOutOfRange},
+		{name: "r = n", r: N, s: sig.S, Q: key.PK, err: ErrNumberIsOutOfRange},
+		{name: "s = n", r: sig.R, s: N, Q: key.PK, err: ErrNumberIsOutOfRange},
+		{name: "negative s", r: sig.R, s: new(big.Int).Neg(sig.S), Q: key.PK, err: ErrNumberIsOutOfRange},
+		{name: "nil public key", r: sig.R, s: sig.S, Q: PublicKey{}, err: ErrNilPublicKey},
+		{name: "point at infinity", r: sig.R, s: sig.S, Q: PublicKey{X: big.NewInt(0), Y: big.NewInt(0)}, err: ErrZeroPublicKey},
+		{name: "not on curve", r: sig.R, s: sig.S, Q: PublicKey{X: key.PK.X, Y: new(big.Int).Add(key.PK.Y, big.NewInt(1))}, err: ErrPublicKeyIsNotOnCurve},
+	}
+
+	for _, c := range cases {
+		isVerified, err := Verify(curve, msg, c.r, c.s, c.Q)
+		if isVerified || err != c.err {
+			t.Errorf("ecdsa.Verify: wrong result for %s, err = %v", c.name, err)
+		}
+	}
+}
+
+func generateBatch(t testing.TB, curve elliptic.Curve, amount int) []BatchItem {
+	items := make([]BatchItem, amount)
+
+	for i := range items {
+		key, err := GeneratePrivateKey(curve)
+		if err != nil {
+			t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		msg := []byte(fmt.Sprintf("message %d", i))
+
+		sig, err := Sign(curve, msg, key.D, nil)
+		if err != nil {
+			t.Fatalf("ecdsa.Sign: unexcpected error `%s`", err.Error())
+		}
+
+		items[i] = BatchItem{Msg: msg, Signature: sig, PublicKey: key.PK}
+	}
+
+	return items
+}
+
+func TestVerifyBatch(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), secp256k1.S256()} {
+		name := curve.Params().Name
+		items := generateBatch(t, curve, 24)
+
+		for _, workers := range []int{0, 1, 5, 100} {
+			for i, result := range VerifyBatch(curve, items, workers) {
+				if !result.Valid || result.Err != nil {
+					t.Errorf("ecdsa.VerifyBatch: valid signature is rejected for %d item on %s, err = %v", i, name, result.Err)
+				}
+			}
+
+			isVerified, err := VerifyBatchRandomized(curve, items, workers)
+			if err != nil {
+				t.Errorf("ecdsa.VerifyBatchRandomized: unexcpected error `%s` on %s", err.Error(), name)
+			}
+			if !isVerified {
+				t.Errorf("ecdsa.VerifyBatchRandomized: valid batch is rejected on %s", name)
+			}
+		}
+
+		// one broken signature makes the whole batch invalid, VerifyBatch shows which one
+		broken := make([]BatchItem, len(items))
+		copy(broken, items)
+		broken[7].Msg = []byte("another message")
+
+		for i, result := range VerifyBatch(curve, broken, 0) {
+			if result.Valid != (i != 7) || result.Err != nil {
+				t.Errorf("ecdsa.VerifyBatch: wrong result for %d item on %s, err = %v", i, name, result.Err)
+			}
+		}
+
+		isVerified, err := VerifyBatchRandomized(curve, broken, 0)
+		if err != nil || isVerified {
+			t.Errorf("ecdsa.VerifyBatchRandomized: invalid batch is accepted on %s, err = %v", name, err)
+		}
+
+		// s -> N - s is valid only with flipped recovery id, R_i must be restored correctly
+		copy(broken, items)
+		broken[3].Signature = &Signature{
+			R: items[3].Signature.R,
+			S: new(big.Int).Sub(curve.Params().N, items[3].Signature.S),
+			V: items[3].Signature.V,
+		}
+
+		isVerified, err = VerifyBatchRandomized(curve, broken, 0)
+		if err != nil || isVerified {
+			t.Errorf("ecdsa.VerifyBatchRandomized: signature with wrong recovery id is accepted on %s, err = %v", name, err)
+		}
+
+		broken[3].Signature.V ^= 1
+
+		isVerified, err = VerifyBatchRandomized(curve, broken, 0)
+		if err != nil || !isVerified {
+			t.Errorf("ecdsa.VerifyBatchRandomized: signature with flipped s and recovery id is rejected on %s, err = %v", name, err)
+		}
+	}
+
+	curve := elliptic.P256()
+	items := generateBatch(t, curve, 4)
+
+	items[1].Signature = nil
+	items[2].PublicKey = PublicKey{X: big.NewInt(0), Y: big.NewInt(0)}
+
+	results := VerifyBatch(curve, items, 0)
+	expectedErrs := []error{nil, ErrNilSignatureValue, ErrZeroPublicKey, nil}
+
+	for i, result := range results {
+		if result.Err != expectedErrs[i] || result.Valid != (expectedErrs[i] == nil) {
+			t.Errorf("ecdsa.VerifyBatch: wrong result for %d item, err = %v", i, result.Err)
+		}
+	}
+
+	if _, err := VerifyBatchRandomized(curve, items, 0); err == nil {
+		t.Errorf("ecdsa.VerifyBatchRandomized: expected error for invalid input")
+	}
+
+	if _, err := VerifyBatchRandomized(curve, nil, 0); err != ErrEmptyBatch {
+		t.Errorf("ecdsa.VerifyBatchRandomized: expected ErrEmptyBatch for empty batch")
+	}
+
+	if results = VerifyBatch(curve, nil, 0); len(results) != 0 {
+		t.Errorf("ecdsa.VerifyBatch: unexpected results for empty batch")
+	}
+}
+
+// TestVerifyBatchDigest signatures with non-default hash are verified by digest
+func TestVerifyBatchDigest(t *testing.T) {
+	curve := elliptic.P256()
+	opts := &Options{Hash: sha512.New384}
+
+	items := make([]BatchItem, 8)
+	for i := range items {
+		key, err := GeneratePrivateKey(curve)
+		if err != nil {
+			t.Fatalf("ecdsa.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		msg := []byte(fmt.Sprintf("message %d", i))
+		digest := hashMessage(sha512.New384, msg)
+
+		// half of signatures are made over message, another half over digest
+		var sig *Signature
+		if i%2 == 0 {
+			sig, err = SignWithOptions(curve, msg, key.D, nil, opts)
+		} else {
+			sig, err = SignDigest(curve, digest, key.D, nil)
+		}
+		if err != nil {
+			t.Fatalf("ecdsa.Sign: unexcpected error `%s`", err.Error())
+		}
+
+		items[i] = BatchItem{Msg: msg, Digest: digest, Signature: sig, PublicKey: key.PK}
+	}
+
+	for i, result := range VerifyBatch(curve, items, 0) {
+		if !result.Valid || result.Err != nil {
+			t.Errorf("ecdsa.VerifyBatch: valid SHA384 signature is rejected for %d item, err = %v", i, result.Err)
+		}
+	}
+
+	isVerified, err := VerifyBatchRandomized(curve, items, 0)
+	if err != nil || !isVerified {
+		t.Errorf("ecdsa.VerifyBatchRandomized: valid SHA384 batch is rejected, err = %v", err)
+	}
+
+	// without digest message is hashed with SHA256, so signatures are invalid
+	for i := range items {
+		items[i].Digest = nil
+	}
+
+	for i, result := range VerifyBatch(curve, items, 0) {
+		if result.Valid {
+			t.Errorf("ecdsa.VerifyBatch: SHA384 signature is accepted as SHA256 one for %d item", i)
+		}
+	}
+
+	if isVerified, _ = VerifyBatchRandomized(curve, items, 0); isVerified {
+		t.Errorf("ecdsa.VerifyBatchRandomized: SHA384 batch is accepted as SHA256 one")
+	}
+}
+
+func benchmarkBatch(b *testing.B, verify func(curve elliptic.Curve, items []BatchItem)) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), secp256k1.S256()} {
+		items := generateBatch(b, curve, 64)
+
+		b.Run(curve.Params().Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				verify(curve, items)
+			}
+		})
+	}
+}
+
+func BenchmarkVerifySequential(b *testing.B) {
+	benchmarkBatch(b, func(curve elliptic.Curve, items []BatchItem) {
+		for _, item := range items {
+			if ok, _ := Verify(curve, item.Msg, item.Signature.R, item.Signature.S, item.PublicKey); !ok {
+				b.Fatal("ecdsa.Verify: valid signature is rejected")
+			}
+		}
+	})
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	benchmarkBatch(b, func(curve elliptic.Curve, items []BatchItem) {
+		for _, result := range VerifyBatch(curve, items, 0) {
+			if !result.Valid {
+				b.Fatal("ecdsa.VerifyBatch: valid signature is rejected")
+			}
+		}
+	})
+}
+
+func BenchmarkVerifyBatchRandomized(b *testing.B) {
+	benchmarkBatch(b, func(curve elliptic.Curve, items []BatchItem) {
+		if ok, _ := VerifyBatchRandomized(curve, items, 0); !ok {
+			b.Fatal("ecdsa.VerifyBatchRandomized: valid batch is rejected")
+		}
+	})
+}
+
+func TestConstantTimeEngine(t *testing.T) {
+	curves := []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521(), secp256k1.S256()}
+
+	for _, curve := range curves {
+		name := curve.Params().Name
+		N := curve.Params().N
+
+		scalars := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(15), big.NewInt(16), new(big.Int).Sub(N, big.NewInt(1))}
+		for i := 0; i < 10; i++ {
+			k, err := rand.Int(rand.Reader, N)
+			if err != nil {
+				t.Fatalf("rand.Int: unexcpected error `%s`", err.Error())
+			}
+			scalars = append(scalars, k)
+		}
+
+		for i, k := range scalars {
+			x, y := scalarBaseMult(curve, k)
+			expectedX, expectedY := curve.ScalarBaseMult(k.Bytes())
+
+			if x.Cmp(expectedX) != 0 || y.Cmp(expectedY) != 0 {
+				t.Errorf("ecdsa.scalarBaseMult: wrong point for %d scalar on %s", i, name)
+			}
+		}
+
+		// arbitrary point, the same scalars and k = 0
+		Qx, Qy := curve.ScalarBaseMult(scalars[len(scalars)-1].Bytes())
+		for i, k := range append(scalars, big.NewInt(0)) {
+			x, y := ScalarMult(curve, Qx, Qy, k)
+			expectedX, expectedY := curve.ScalarMult(Qx, Qy, k.Bytes())
+
+			if x.Cmp(expectedX) != 0 || y.Cmp(expectedY) != 0 {
+				t.Errorf("ecdsa.ScalarMult: wrong point for %d scalar on %s", i, name)
+			}
+		}
+
+		// (N - 1) x G = -G
+		x, y := scalarBaseMult(curve, new(big.Int).Sub(N, big.NewInt(1)))
+		if x.Cmp(curve.Params().Gx) != 0 || y.Cmp(new(big.Int).Sub(curve.Params().P, curve.Params().Gy)) != 0 {
+			t.Errorf("ecdsa.scalarBaseMult: (N - 1) x G != -G on %s", name)
+		}
+
+		// 0 x G and N x G are the point at infinity
+		engine := engineFor(curve)
+		for _, k := range []*big.Int{big.NewInt(0), N} {
+			if x, y := engine.toAffine(engine.scalarMult(&engine.baseTable, k)); x.Sign() != 0 || y.Sign() != 0 {
+				t.Errorf("ecdsa.scalarBaseMult: %d x G is not the point at infinity on %s", k, name)
+			}
+		}
+
+		// field operations are compared with big.Int
+		for _, modulus := range []*big.Int{curve.Params().P, N} {
+			f := newMontgomeryField(modulus)
+
+			for i := 0; i < 20; i++ {
+				a, _ := rand.Int(rand.Reader, modulus)
+				b, _ := rand.Int(rand.Reader, modulus)
+				if i == 0 {
+					a.Sub(modulus, big.NewInt(1))
+				}
+
+				am, bm := f.fromBig(a), f.fromBig(b)
+
+				sum := new(big.Int).Add(a, b)
+				diff := new(big.Int).Sub(a, b)
+				prod := new(big.Int).Mul(a, b)
+
+				if f.toBig(f.add(am, bm)).Cmp(sum.Mod(sum, modulus)) != 0 ||
+					f.toBig(f.sub(am, bm)).Cmp(diff.Mod(diff, modulus)) != 0 ||
+					f.toBig(f.mul(am, bm)).Cmp(prod.Mod(prod, modulus)) != 0 ||
+					f.toBig(f.inverse(am)).Cmp(new(big.Int).ModInverse(a, modulus)) != 0 {
+					t.Errorf("ecdsa.montgomeryField: wrong result for %d vector on %s", i, name)
+				}
+			}
+		}
+	}
+}
+
+// TestTimingLeakage dudect-style statistical test: scalar multiplication is timed for two classes of nonces
+// (fixed short one and random ones), Welch's t-test must not see the difference. It is slow and depends on
+// the machine, so it is run only with ECDSA_TIMING_TEST=1 go test -run TestTimingLeakage -v
+func TestTimingLeakage(t *testing.T) {
+	if os.Getenv("ECDSA_TIMING_TEST") == "" {
+		t.Skip("set ECDSA_TIMING_TEST=1 to run timing test")
+	}
+
+	const measurements = 20000
+	// |t| above it means timing leakage with high confidence (the same threshold as in dudect)
+	const threshold = 4.5
+
+	curve := elliptic.P256()
+	N := curve.Params().N
+
+	measure := func(name string, scalarMult func(k *big.Int)) float64 {
+		classes := make([]int, measurements)
+		inputs := make([]*big.Int, measurements)
+		durations := make([]float64, measurements)
+
+		for i := range inputs {
+			classes[i] = mathrand.Intn(2)
+
+			if classes[i] == 0 {
+				inputs[i] = big.NewInt(1)
+				continue
+			}
+
+			k, err := rand.Int(rand.Reader, N)
+			if err != nil {
+				t.Fatalf("rand.Int: unexcpected error `%s`", err.Error())
+			}
+			inputs[i] = k
+		}
+
+		for i, k := range inputs {
+			start := time.Now()
+			scalarMult(k)
+			durations[i] = float64(time.Since(start).Nanoseconds())
+		}
+
+		// measurements above 90th percentile are mostly noise (interrupts, GC), they are cropped
+		sorted := append([]float64(nil), durations...)
+		sort.Float64s(sorted)
+		limit := sorted[len(sorted)*9/10]
+
+		var samples [2][]float64
+		for i, duration := range durations {
+			if duration <= limit {
+				samples[classes[i]] = append(samples[classes[i]], duration)
+			}
+		}
+
+		tValue := welchT(samples[0], samples[1])
+		t.Logf("%s: t = %.2f", name, tValue)
+
+		return tValue
+	}
+
+	if tValue := measure("constant time scalarBaseMult", func(k *big.Int) { scalarBaseMult(curve, k) }); math.Abs(tValue) > threshold {
+		t.Errorf("ecdsa.scalarBaseMult: timing depends on scalar, t = %.2f", tValue)
+	}
+
+	// for comparison, double-and-add on big.Int is expected to show huge t
+	measure("secp256k1 double-and-add", func(k *big.Int) { secp256k1.S256().ScalarBaseMult(k.Bytes()) })
+}
+
+// welchT Welch's t-statistic for two samples with different variances
+func welchT(a, b []float64) float64 {
+	meanAndVariance := func(samples []float64) (float64, float64) {
+		var mean, variance float64
+		for _, s := range samples {
+			mean += s
+		}
+		mean /= float64(len(samples))
+
+		for _, s := range samples {
+			variance += (s - mean) * (s - mean)
+		}
+		variance /= float64(len(samples) - 1)
+
+		return mean, variance
+	}
+
+	meanA, varianceA := meanAndVariance(a)
+	meanB, varianceB := meanAndVariance(b)
+
+	return (meanA - meanB) / math.Sqrt(varianceA/float64(len(a))+varianceB/float64(len(b)))
+}
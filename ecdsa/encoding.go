@@ -0,0 +1,101 @@
+package ecdsa
+
+import (
+	"crypto/elliptic"
+	"encoding/asn1"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidSignatureEncoding = errors.New("signature encoding is invalid")
+	ErrNegativeSignatureValue   = errors.New("signature value is negative")
+	ErrSignatureTrailingData    = errors.New("signature has trailing data")
+)
+
+// asn1Signature ECDSA-Sig-Value ::= SEQUENCE { r INTEGER, s INTEGER }
+type asn1Signature struct {
+	R *big.Int
+	S *big.Int
+}
+
+// MarshalASN1 DER encoding of the signature, the one used by OpenSSL, X.509 and TLS
+func (sig *Signature) MarshalASN1() ([]byte, error) {
+	if sig.R == nil || sig.S == nil {
+		return nil, ErrInvalidSignatureEncoding
+	}
+
+	if sig.R.Sign() < 0 || sig.S.Sign() < 0 {
+		return nil, ErrNegativeSignatureValue
+	}
+
+	data, err := asn1.Marshal(asn1Signature{R: sig.R, S: sig.S})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal signature")
+	}
+
+	return data, nil
+}
+
+// ParseASN1 strict DER parsing: non-minimal encodings, trailing data and negative integers are rejected
+func ParseASN1(data []byte) (*Signature, error) {
+	var sig asn1Signature
+
+	rest, err := asn1.Unmarshal(data, &sig)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidSignatureEncoding, err.Error())
+	}
+
+	if len(rest) != 0 {
+		return nil, ErrSignatureTrailingData
+	}
+
+	// re-encoding must give the same bytes, otherwise some non-DER form was used
+	reencoded, err := asn1.Marshal(sig)
+	if err != nil || string(reencoded) != string(data) {
+		return nil, ErrInvalidSignatureEncoding
+	}
+
+	if sig.R.Sign() < 0 || sig.S.Sign() < 0 {
+		return nil, ErrNegativeSignatureValue
+	}
+
+	return &Signature{R: sig.R, S: sig.S}, nil
+}
+
+// MarshalRaw fixed-width r || s encoding (IEEE P1363), each value takes byte length of curve order
+func (sig *Signature) MarshalRaw(curve elliptic.Curve) ([]byte, error) {
+	size := (curve.Params().N.BitLen() + 7) / 8
+
+	if sig.R == nil || sig.S == nil {
+		return nil, ErrInvalidSignatureEncoding
+	}
+
+	if sig.R.Sign() < 0 || sig.S.Sign() < 0 {
+		return nil, ErrNegativeSignatureValue
+	}
+
+	if sig.R.BitLen() > size*8 || sig.S.BitLen() > size*8 {
+		return nil, ErrNumberIsOutOfRange
+	}
+
+	result := make([]byte, 2*size)
+	sig.R.FillBytes(result[:size])
+	sig.S.FillBytes(result[size:])
+
+	return result, nil
+}
+
+func ParseRaw(curve elliptic.Curve, data []byte) (*Signature, error) {
+	size := (curve.Params().N.BitLen() + 7) / 8
+
+	if len(data) != 2*size {
+		return nil, ErrInvalidSignatureEncoding
+	}
+
+	return &Signature{
+		R: new(big.Int).SetBytes(data[:size]),
+		S: new(big.Int).SetBytes(data[size:]),
+	}, nil
+}
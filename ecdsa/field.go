@@ -0,0 +1,223 @@
+package ecdsa
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// maxLimbs enough 64-bit limbs for P-521
+const maxLimbs = 9
+
+// fieldElement number in Montgomery form (a*R mod m, R = 2^(64*n)), little endian limbs, only first n limbs are used
+type fieldElement [maxLimbs]uint64
+
+// montgomeryField arithmetic modulo odd m on fixed-width limbs, every operation does the same amount of work
+// for any values, so its timing does not depend on secrets (modulus itself is public)
+type montgomeryField struct {
+	n       int
+	m       fieldElement
+	mInv    uint64 // -m^-1 mod 2^64
+	rr      fieldElement
+	r       fieldElement // 1 in Montgomery form
+	modulus *big.Int
+}
+
+func newMontgomeryField(modulus *big.Int) *montgomeryField {
+	n := (modulus.BitLen() + 63) / 64
+	if n > maxLimbs || modulus.Bit(0) == 0 {
+		panic("modulus is not supported")
+	}
+
+	f := &montgomeryField{n: n, modulus: new(big.Int).Set(modulus)}
+	f.m = f.limbs(modulus)
+
+	// Newton iteration, each step doubles amount of correct bits of m^-1 mod 2^64
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - f.m[0]*inv
+	}
+	f.mInv = -inv
+
+	// R^2 mod m, it converts numbers to Montgomery form
+	rr := new(big.Int).Lsh(big.NewInt(1), uint(128*n))
+	f.rr = f.limbs(rr.Mod(rr, modulus))
+	f.r = f.fromBig(big.NewInt(1))
+
+	return f
+}
+
+// limbs splits 0 <= x < m into n limbs, x is written to fixed size buffer, so its bit length does not matter
+func (f *montgomeryField) limbs(x *big.Int) fieldElement {
+	buf := x.FillBytes(make([]byte, 8*f.n))
+
+	var result fieldElement
+	for i := 0; i < f.n; i++ {
+		result[i] = binary.BigEndian.Uint64(buf[8*(f.n-1-i):])
+	}
+
+	return result
+}
+
+// fromBig converts x to Montgomery form, x is reduced if it is not less than m
+func (f *montgomeryField) fromBig(x *big.Int) fieldElement {
+	if x.Sign() < 0 || x.Cmp(f.modulus) >= 0 {
+		x = new(big.Int).Mod(x, f.modulus)
+	}
+
+	return f.mul(f.limbs(x), f.rr)
+}
+
+// toBig converts a back from Montgomery form
+func (f *montgomeryField) toBig(a fieldElement) *big.Int {
+	var one fieldElement
+	one[0] = 1
+
+	a = f.mul(a, one)
+
+	buf := make([]byte, 8*f.n)
+	for i := 0; i < f.n; i++ {
+		binary.BigEndian.PutUint64(buf[8*(f.n-1-i):], a[i])
+	}
+
+	return new(big.Int).SetBytes(buf)
+}
+
+func (f *montgomeryField) one() fieldElement {
+	return f.r
+}
+
+// reduce t - m if t (with extra carry bit) is not less than m, t < 2m
+func (f *montgomeryField) reduce(t fieldElement, carry uint64) fieldElement {
+	var u fieldElement
+	var borrow uint64
+
+	for i := 0; i < f.n; i++ {
+		u[i], borrow = bits.Sub64(t[i], f.m[i], borrow)
+	}
+
+	// t >= m when there is carry or subtraction has no borrow
+	return f.selectElement(carry|(borrow^1), u, t)
+}
+
+func (f *montgomeryField) add(a, b fieldElement) fieldElement {
+	var t fieldElement
+	var carry uint64
+
+	for i := 0; i < f.n; i++ {
+		t[i], carry = bits.Add64(a[i], b[i], carry)
+	}
+
+	return f.reduce(t, carry)
+}
+
+func (f *montgomeryField) sub(a, b fieldElement) fieldElement {
+	var t fieldElement
+	var borrow uint64
+
+	for i := 0; i < f.n; i++ {
+		t[i], borrow = bits.Sub64(a[i], b[i], borrow)
+	}
+
+	// add m back when a < b
+	mask := -borrow
+	var carry uint64
+	for i := 0; i < f.n; i++ {
+		t[i], carry = bits.Add64(t[i], f.m[i]&mask, carry)
+	}
+
+	return t
+}
+
+// mul a*b*R^-1 mod m, CIOS Montgomery multiplication
+func (f *montgomeryField) mul(a, b fieldElement) fieldElement {
+	var t [maxLimbs + 2]uint64
+	n := f.n
+
+	for i := 0; i < n; i++ {
+		//t = t + a*b[i]
+		var c uint64
+		for j := 0; j < n; j++ {
+			hi, lo := bits.Mul64(a[j], b[i])
+			var cc uint64
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+
+		var cc uint64
+		t[n], cc = bits.Add64(t[n], c, 0)
+		t[n+1] = cc
+
+		//t = (t + mu*m) / 2^64, mu is chosen so the lowest limb becomes zero
+		mu := t[0] * f.mInv
+		hi, lo := bits.Mul64(mu, f.m[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+
+		for j := 1; j < n; j++ {
+			hi, lo = bits.Mul64(mu, f.m[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+
+		t[n-1], cc = bits.Add64(t[n], c, 0)
+		t[n] = t[n+1] + cc
+	}
+
+	var result fieldElement
+	copy(result[:n], t[:n])
+
+	return f.reduce(result, t[n])
+}
+
+func (f *montgomeryField) square(a fieldElement) fieldElement {
+	return f.mul(a, a)
+}
+
+// exp a^e, e is public (e.g. m - 2), so square-and-multiply on its bits does not leak a
+func (f *montgomeryField) exp(a fieldElement, e *big.Int) fieldElement {
+	result := f.one()
+
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		result = f.square(result)
+
+		if e.Bit(i) == 1 {
+			result = f.mul(result, a)
+		}
+	}
+
+	return result
+}
+
+// inverse a^(m - 2), m is prime
+func (f *montgomeryField) inverse(a fieldElement) fieldElement {
+	return f.exp(a, new(big.Int).Sub(f.modulus, big.NewInt(2)))
+}
+
+// isZero 1 if a = 0, otherwise 0
+func (f *montgomeryField) isZero(a fieldElement) uint64 {
+	var acc uint64
+	for i := 0; i < f.n; i++ {
+		acc |= a[i]
+	}
+
+	return ((acc | -acc) >> 63) ^ 1
+}
+
+// selectElement a if flag is 1, b if flag is 0
+func (f *montgomeryField) selectElement(flag uint64, a, b fieldElement) fieldElement {
+	mask := -flag
+
+	var result fieldElement
+	for i := 0; i < f.n; i++ {
+		result[i] = (a[i] & mask) | (b[i] &^ mask)
+	}
+
+	return result
+}
@@ -0,0 +1,412 @@
+package ecdsa
+
+import (
+	"crypto/elliptic"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+
+	"github.com/mhrynenko/cryptography_course/secp256k1"
+	"github.com/pkg/errors"
+)
+
+const (
+	pointUncompressed   = 0x04
+	pointCompressedEven = 0x02
+	pointCompressedOdd  = 0x03
+
+	ecPrivateKeyVersion = 1
+	pkcs8Version        = 0
+
+	pemECPrivateKey = "EC PRIVATE KEY"
+	pemPrivateKey   = "PRIVATE KEY"
+	pemPublicKey    = "PUBLIC KEY"
+)
+
+var (
+	ErrInvalidPointEncoding = errors.New("point encoding is invalid")
+	ErrInvalidKeyEncoding   = errors.New("key encoding is invalid")
+	ErrUnsupportedCurve     = errors.New("curve is not supported")
+	ErrUnsupportedAlgorithm = errors.New("key algorithm is not supported")
+	ErrPublicKeyMismatch    = errors.New("public key does not match private key")
+	ErrNoPEMBlock           = errors.New("no suitable PEM block found")
+)
+
+var oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+
+type namedCurve struct {
+	curve elliptic.Curve
+	oid   asn1.ObjectIdentifier
+}
+
+var namedCurves = []namedCurve{
+	{curve: elliptic.P224(), oid: asn1.ObjectIdentifier{1, 3, 132, 0, 33}},
+	{curve: elliptic.P256(), oid: asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}},
+	{curve: elliptic.P384(), oid: asn1.ObjectIdentifier{1, 3, 132, 0, 34}},
+	{curve: elliptic.P521(), oid: asn1.ObjectIdentifier{1, 3, 132, 0, 35}},
+	{curve: secp256k1.S256(), oid: asn1.ObjectIdentifier{1, 3, 132, 0, 10}},
+}
+
+// ecPrivateKey SEC1 ECPrivateKey structure (RFC 5915)
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+type algorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.ObjectIdentifier
+}
+
+// pkcs8 PrivateKeyInfo structure (RFC 5208)
+type pkcs8 struct {
+	Version    int
+	Algo       algorithmIdentifier
+	PrivateKey []byte
+}
+
+// publicKeyInfo SubjectPublicKeyInfo structure (RFC 5480)
+type publicKeyInfo struct {
+	Algo      algorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+func curveOID(curve elliptic.Curve) (asn1.ObjectIdentifier, error) {
+	for _, named := range namedCurves {
+		if named.curve == curve {
+			return named.oid, nil
+		}
+	}
+
+	return nil, ErrUnsupportedCurve
+}
+
+func curveByOID(oid asn1.ObjectIdentifier) (elliptic.Curve, error) {
+	for _, named := range namedCurves {
+		if named.oid.Equal(oid) {
+			return named.curve, nil
+		}
+	}
+
+	return nil, ErrUnsupportedCurve
+}
+
+// Marshal SEC1 uncompressed point encoding 0x04 || X || Y
+func (pk *PublicKey) Marshal(curve elliptic.Curve) []byte {
+	size := (curve.Params().BitSize + 7) / 8
+
+	result := make([]byte, 1+2*size)
+	result[0] = pointUncompressed
+	pk.X.FillBytes(result[1 : 1+size])
+	pk.Y.FillBytes(result[1+size:])
+
+	return result
+}
+
+// MarshalCompressed SEC1 compressed point encoding 0x02/0x03 || X, prefix shows parity of Y
+func (pk *PublicKey) MarshalCompressed(curve elliptic.Curve) []byte {
+	size := (curve.Params().BitSize + 7) / 8
+
+	result := make([]byte, 1+size)
+	result[0] = byte(pointCompressedEven + pk.Y.Bit(0))
+	pk.X.FillBytes(result[1:])
+
+	return result
+}
+
+// ParsePublicKey parses both compressed and uncompressed SEC1 points, point must be on curve
+func ParsePublicKey(curve elliptic.Curve, data []byte) (*PublicKey, error) {
+	size := (curve.Params().BitSize + 7) / 8
+
+	if len(data) == 0 {
+		return nil, ErrInvalidPointEncoding
+	}
+
+	var x, y *big.Int
+
+	switch data[0] {
+	case pointUncompressed:
+		if len(data) != 1+2*size {
+			return nil, ErrInvalidPointEncoding
+		}
+
+		x = new(big.Int).SetBytes(data[1 : 1+size])
+		y = new(big.Int).SetBytes(data[1+size:])
+	case pointCompressedEven, pointCompressedOdd:
+		if len(data) != 1+size {
+			return nil, ErrInvalidPointEncoding
+		}
+
+		var err error
+
+		x = new(big.Int).SetBytes(data[1:])
+		y, err = decompressPoint(curve, x, data[0] == pointCompressedOdd)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInvalidPointEncoding
+	}
+
+	if x.Cmp(curve.Params().P) >= 0 || y.Cmp(curve.Params().P) >= 0 {
+		return nil, ErrInvalidPointEncoding
+	}
+
+	if !curve.IsOnCurve(x, y) {
+		return nil, ErrPublicKeyIsNotOnCurve
+	}
+
+	return &PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// MarshalSEC1PrivateKey DER encoding of SEC1 `EC PRIVATE KEY`, the one `openssl ec` uses by default
+func MarshalSEC1PrivateKey(curve elliptic.Curve, key *PrivateKey) ([]byte, error) {
+	oid, err := curveOID(curve)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalECPrivateKey(curve, key, oid)
+}
+
+func marshalECPrivateKey(curve elliptic.Curve, key *PrivateKey, oid asn1.ObjectIdentifier) ([]byte, error) {
+	size := (curve.Params().N.BitLen() + 7) / 8
+
+	if key == nil || key.D == nil {
+		return nil, ErrNilPrivateKey
+	}
+
+	if key.D.Sign() <= 0 || key.D.Cmp(curve.Params().N) >= 0 {
+		return nil, ErrNumberIsOutOfRange
+	}
+
+	publicKey := key.PK.Marshal(curve)
+
+	data, err := asn1.Marshal(ecPrivateKey{
+		Version:       ecPrivateKeyVersion,
+		PrivateKey:    key.D.FillBytes(make([]byte, size)),
+		NamedCurveOID: oid,
+		PublicKey:     asn1.BitString{Bytes: publicKey, BitLength: len(publicKey) * 8},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal ec private key")
+	}
+
+	return data, nil
+}
+
+// ParseSEC1PrivateKey parses SEC1 `EC PRIVATE KEY`, curve is taken from the key parameters
+func ParseSEC1PrivateKey(data []byte) (elliptic.Curve, *PrivateKey, error) {
+	return parseECPrivateKey(nil, data)
+}
+
+// parseECPrivateKey oid is set when key is wrapped in PKCS#8, there curve is stored outside of the key
+func parseECPrivateKey(oid asn1.ObjectIdentifier, data []byte) (elliptic.Curve, *PrivateKey, error) {
+	var key ecPrivateKey
+
+	rest, err := asn1.Unmarshal(data, &key)
+	if err != nil {
+		return nil, nil, errors.Wrap(ErrInvalidKeyEncoding, err.Error())
+	}
+
+	if len(rest) != 0 || key.Version != ecPrivateKeyVersion {
+		return nil, nil, ErrInvalidKeyEncoding
+	}
+
+	if len(key.NamedCurveOID) != 0 {
+		oid = key.NamedCurveOID
+	}
+
+	curve, err := curveByOID(oid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	N := curve.Params().N
+	if len(key.PrivateKey) != (N.BitLen()+7)/8 {
+		return nil, nil, ErrInvalidKeyEncoding
+	}
+
+	d := new(big.Int).SetBytes(key.PrivateKey)
+	if d.Sign() == 0 || d.Cmp(N) >= 0 {
+		return nil, nil, ErrNumberIsOutOfRange
+	}
+
+	//d x G in constant time, the same as in GeneratePrivateKey
+	pubX, pubY := scalarBaseMult(curve, d)
+	result := &PrivateKey{
+		PK: PublicKey{Curve: curve, X: pubX, Y: pubY},
+		D:  d,
+	}
+
+	if len(key.PublicKey.Bytes) != 0 {
+		pk, err := ParsePublicKey(curve, key.PublicKey.RightAlign())
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to parse public key")
+		}
+
+		if pk.X.Cmp(pubX) != 0 || pk.Y.Cmp(pubY) != 0 {
+			return nil, nil, ErrPublicKeyMismatch
+		}
+	}
+
+	return curve, result, nil
+}
+
+// MarshalPKCS8PrivateKey DER encoding of PKCS#8 `PRIVATE KEY`
+func MarshalPKCS8PrivateKey(curve elliptic.Curve, key *PrivateKey) ([]byte, error) {
+	oid, err := curveOID(curve)
+	if err != nil {
+		return nil, err
+	}
+
+	// curve is stored in algorithm parameters, so it is omitted in inner key
+	inner, err := marshalECPrivateKey(curve, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := asn1.Marshal(pkcs8{
+		Version:    pkcs8Version,
+		Algo:       algorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: oid},
+		PrivateKey: inner,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal pkcs8 private key")
+	}
+
+	return data, nil
+}
+
+func ParsePKCS8PrivateKey(data []byte) (elliptic.Curve, *PrivateKey, error) {
+	var key pkcs8
+
+	rest, err := asn1.Unmarshal(data, &key)
+	if err != nil {
+		return nil, nil, errors.Wrap(ErrInvalidKeyEncoding, err.Error())
+	}
+
+	if len(rest) != 0 || key.Version != pkcs8Version {
+		return nil, nil, ErrInvalidKeyEncoding
+	}
+
+	if !key.Algo.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, nil, ErrUnsupportedAlgorithm
+	}
+
+	return parseECPrivateKey(key.Algo.Parameters, key.PrivateKey)
+}
+
+// MarshalPKIXPublicKey DER encoding of SubjectPublicKeyInfo `PUBLIC KEY`
+func MarshalPKIXPublicKey(curve elliptic.Curve, pk *PublicKey) ([]byte, error) {
+	oid, err := curveOID(curve)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey := pk.Marshal(curve)
+
+	data, err := asn1.Marshal(publicKeyInfo{
+		Algo:      algorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: oid},
+		PublicKey: asn1.BitString{Bytes: publicKey, BitLength: len(publicKey) * 8},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal public key info")
+	}
+
+	return data, nil
+}
+
+func ParsePKIXPublicKey(data []byte) (elliptic.Curve, *PublicKey, error) {
+	var info publicKeyInfo
+
+	rest, err := asn1.Unmarshal(data, &info)
+	if err != nil {
+		return nil, nil, errors.Wrap(ErrInvalidKeyEncoding, err.Error())
+	}
+
+	if len(rest) != 0 {
+		return nil, nil, ErrInvalidKeyEncoding
+	}
+
+	if !info.Algo.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, nil, ErrUnsupportedAlgorithm
+	}
+
+	curve, err := curveByOID(info.Algo.Parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pk, err := ParsePublicKey(curve, info.PublicKey.RightAlign())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return curve, pk, nil
+}
+
+// MarshalPrivateKeyPEM SEC1 private key in PEM armor
+func MarshalPrivateKeyPEM(curve elliptic.Curve, key *PrivateKey) ([]byte, error) {
+	der, err := MarshalSEC1PrivateKey(curve, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemECPrivateKey, Bytes: der}), nil
+}
+
+// MarshalPKCS8PrivateKeyPEM PKCS#8 private key in PEM armor
+func MarshalPKCS8PrivateKeyPEM(curve elliptic.Curve, key *PrivateKey) ([]byte, error) {
+	der, err := MarshalPKCS8PrivateKey(curve, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemPrivateKey, Bytes: der}), nil
+}
+
+func MarshalPublicKeyPEM(curve elliptic.Curve, pk *PublicKey) ([]byte, error) {
+	der, err := MarshalPKIXPublicKey(curve, pk)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemPublicKey, Bytes: der}), nil
+}
+
+// ParsePrivateKeyPEM accepts both `EC PRIVATE KEY` and `PRIVATE KEY` blocks, other blocks (e.g. `EC PARAMETERS`) are skipped
+func ParsePrivateKeyPEM(data []byte) (elliptic.Curve, *PrivateKey, error) {
+	for {
+		var block *pem.Block
+
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, nil, ErrNoPEMBlock
+		}
+
+		switch block.Type {
+		case pemECPrivateKey:
+			return ParseSEC1PrivateKey(block.Bytes)
+		case pemPrivateKey:
+			return ParsePKCS8PrivateKey(block.Bytes)
+		}
+	}
+}
+
+func ParsePublicKeyPEM(data []byte) (elliptic.Curve, *PublicKey, error) {
+	for {
+		var block *pem.Block
+
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, nil, ErrNoPEMBlock
+		}
+
+		if block.Type == pemPublicKey {
+			return ParsePKIXPublicKey(block.Bytes)
+		}
+	}
+}
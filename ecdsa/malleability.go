@@ -0,0 +1,30 @@
+package ecdsa
+
+import (
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+var ErrHighS = errors.New("signature s is bigger than N/2")
+
+// IsLowS (r, s) and (r, N - s) are both valid signatures, only the one with s <= N/2 is treated as canonical
+func IsLowS(curve elliptic.Curve, s *big.Int) bool {
+	halfOrder := new(big.Int).Rsh(curve.Params().N, 1)
+
+	return s.Cmp(halfOrder) <= 0
+}
+
+// Normalize returns signature with low s, negating s means negating k, so parity of recovery id is flipped as well
+func (sig *Signature) Normalize(curve elliptic.Curve) *Signature {
+	if IsLowS(curve, sig.S) {
+		return &Signature{R: new(big.Int).Set(sig.R), S: new(big.Int).Set(sig.S), V: sig.V}
+	}
+
+	return &Signature{
+		R: new(big.Int).Set(sig.R),
+		S: new(big.Int).Sub(curve.Params().N, sig.S),
+		V: sig.V ^ 1,
+	}
+}
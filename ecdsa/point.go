@@ -0,0 +1,108 @@
+package ecdsa
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// curveA elliptic.CurveParams has only B (it assumes a = -3 of NIST curves), so a is restored from generator point:
+// a = (Gy^2 - Gx^3 - b) / Gx mod p, this way the same code works for curves like secp256k1 where a = 0
+func curveA(curve elliptic.Curve) *big.Int {
+	params := curve.Params()
+	P := params.P
+
+	a := new(big.Int).Mul(params.Gy, params.Gy)
+	a.Sub(a, new(big.Int).Exp(params.Gx, big.NewInt(3), P))
+	a.Sub(a, params.B)
+	a.Mul(a, new(big.Int).ModInverse(params.Gx, P))
+
+	return a.Mod(a, P)
+}
+
+// decompressPoint finds Y with required parity from equation y^2 = x^3 + a*x + b mod p
+func decompressPoint(curve elliptic.Curve, x *big.Int, odd bool) (*big.Int, error) {
+	P := curve.Params().P
+
+	if x.Cmp(P) >= 0 {
+		return nil, ErrInvalidPointEncoding
+	}
+
+	//x^3 + a*x + b
+	rhs := new(big.Int).Exp(x, big.NewInt(3), P)
+	rhs.Add(rhs, new(big.Int).Mul(curveA(curve), x))
+	rhs.Add(rhs, curve.Params().B)
+	rhs.Mod(rhs, P)
+
+	y := modSqrt(rhs, P)
+	if y == nil {
+		return nil, ErrPublicKeyIsNotOnCurve
+	}
+
+	if odd != (y.Bit(0) == 1) {
+		// y = 0 has no pair with another parity
+		if y.Sign() == 0 {
+			return nil, ErrPublicKeyIsNotOnCurve
+		}
+
+		y.Sub(P, y)
+	}
+
+	return y, nil
+}
+
+// modSqrt square root of a modulo odd prime p, nil when a is not a quadratic residue.
+// For p = 3 mod 4 (secp256k1, P-256, P-384, P-521) it is a^((p+1)/4), P-224 has p = 1 mod 4, so Tonelli-Shanks is used
+func modSqrt(a, p *big.Int) *big.Int {
+	a = new(big.Int).Mod(a, p)
+	if a.Sign() == 0 {
+		return a
+	}
+
+	//Euler's criterion: a^((p-1)/2) = 1 for quadratic residue
+	pMinus1 := new(big.Int).Sub(p, big.NewInt(1))
+	if new(big.Int).Exp(a, new(big.Int).Rsh(pMinus1, 1), p).Cmp(big.NewInt(1)) != 0 {
+		return nil
+	}
+
+	if p.Bit(1) == 1 {
+		exp := new(big.Int).Add(p, big.NewInt(1))
+		return exp.Exp(a, exp.Rsh(exp, 2), p)
+	}
+
+	//p - 1 = q * 2^s, q is odd
+	s := pMinus1.TrailingZeroBits()
+	q := new(big.Int).Rsh(pMinus1, s)
+
+	//z is any quadratic non-residue
+	z := big.NewInt(2)
+	for new(big.Int).Exp(z, new(big.Int).Rsh(pMinus1, 1), p).Cmp(pMinus1) != 0 {
+		z.Add(z, big.NewInt(1))
+	}
+
+	m := s
+	c := new(big.Int).Exp(z, q, p)
+	t := new(big.Int).Exp(a, q, p)
+	r := new(big.Int).Exp(a, new(big.Int).Rsh(new(big.Int).Add(q, big.NewInt(1)), 1), p)
+
+	//invariant r^2 = a*t, loop ends when t = 1
+	for t.Cmp(big.NewInt(1)) != 0 {
+		//the least i with t^(2^i) = 1
+		i := uint(0)
+		for t2 := new(big.Int).Set(t); t2.Cmp(big.NewInt(1)) != 0; i++ {
+			t2.Mul(t2, t2).Mod(t2, p)
+		}
+
+		//b = c^(2^(m-i-1))
+		b := new(big.Int).Set(c)
+		for j := uint(0); j < m-i-1; j++ {
+			b.Mul(b, b).Mod(b, p)
+		}
+
+		m = i
+		c.Mul(b, b).Mod(c, p)
+		t.Mul(t, c).Mod(t, p)
+		r.Mul(r, b).Mod(r, p)
+	}
+
+	return r
+}
@@ -0,0 +1,71 @@
+package ecdsa
+
+import (
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidRecoveryID = errors.New("recovery id is invalid")
+	ErrRecoveryFailed    = errors.New("public key can not be recovered")
+)
+
+// RecoverPublicKey restores signer public key from message and signature with recovery id (sig.V):
+// Q = r^-1 * (s*R - H(m)*G), where R is the point k x P restored from r and V
+func RecoverPublicKey(curve elliptic.Curve, msg []byte, sig *Signature) (*PublicKey, error) {
+	return RecoverPublicKeyFromDigest(curve, hashMessage(hashFunc(curve), msg), sig)
+}
+
+// RecoverPublicKeyFromDigest the same as RecoverPublicKey, but message hash is already computed (e.g. Keccak256 in Ethereum)
+func RecoverPublicKeyFromDigest(curve elliptic.Curve, digest []byte, sig *Signature) (*PublicKey, error) {
+	N := curve.Params().N
+
+	if sig.V > 3 {
+		return nil, ErrInvalidRecoveryID
+	}
+
+	if sig.R == nil || sig.S == nil || sig.R.Sign() <= 0 || sig.R.Cmp(N) >= 0 || sig.S.Sign() <= 0 || sig.S.Cmp(N) >= 0 {
+		return nil, ErrNumberIsOutOfRange
+	}
+
+	//x = r + j*n
+	x := new(big.Int).Set(sig.R)
+	if sig.V&2 != 0 {
+		x.Add(x, N)
+	}
+
+	if x.Cmp(curve.Params().P) >= 0 {
+		return nil, ErrInvalidRecoveryID
+	}
+
+	y, err := decompressPoint(curve, x, sig.V&1 == 1)
+	if err != nil {
+		return nil, errors.Wrap(ErrRecoveryFailed, err.Error())
+	}
+
+	h := bits2int(digest, N)
+
+	//pow(r, -1)
+	rInverse := new(big.Int).ModInverse(sig.R, N)
+
+	//u1 = -H(m)*r^-1 modN, u2 = s*r^-1 modN
+	u1 := new(big.Int).Mul(h, rInverse)
+	u1.Neg(u1)
+	u1.Mod(u1, N)
+
+	u2 := new(big.Int).Mul(sig.S, rInverse)
+	u2.Mod(u2, N)
+
+	//Q = u1*G + u2*R
+	x1, y1 := curve.ScalarBaseMult(u1.Bytes())
+	x2, y2 := curve.ScalarMult(x, y, u2.Bytes())
+	qx, qy := curve.Add(x1, y1, x2, y2)
+
+	if qx.Sign() == 0 && qy.Sign() == 0 {
+		return nil, ErrRecoveryFailed
+	}
+
+	return &PublicKey{Curve: curve, X: qx, Y: qy}, nil
+}
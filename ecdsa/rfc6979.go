@@ -0,0 +1,111 @@
+package ecdsa
+
+import (
+	"crypto/elliptic"
+	"hash"
+	"math/big"
+
+	"github.com/mhrynenko/cryptography_course/hmac"
+)
+
+// generateK deterministic nonce generation with HMAC_DRBG, RFC 6979 section 3.2
+func generateK(curve elliptic.Curve, hashFunc func() hash.Hash, d *big.Int, msgHash []byte) *big.Int {
+	return newNonceGenerator(curve, hashFunc, d, msgHash).next()
+}
+
+// nonceGenerator HMAC_DRBG state, it is kept between candidates, so when k gives r = 0 or s = 0
+// the next one is derived as RFC 6979 step h.3 requires
+type nonceGenerator struct {
+	hashFunc func() hash.Hash
+	N        *big.Int
+	K, V     []byte
+	// started the first candidate is already returned, state must be updated before the next one
+	started bool
+}
+
+func newNonceGenerator(curve elliptic.Curve, hashFunc func() hash.Hash, d *big.Int, msgHash []byte) *nonceGenerator {
+	N := curve.Params().N
+	hashSize := hashFunc().Size()
+
+	privateKey := int2octets(d, N)
+	hashOctets := bits2octets(msgHash, N)
+
+	//V = 0x01 0x01 0x01 ... 0x01
+	V := make([]byte, hashSize)
+	for i := range V {
+		V[i] = 0x01
+	}
+
+	//K = 0x00 0x00 0x00 ... 0x00
+	K := make([]byte, hashSize)
+
+	//K = HMAC_K(V || 0x00 || int2octets(x) || bits2octets(h1)), V = HMAC_K(V)
+	K = computeMAC(hashFunc, K, V, []byte{0x00}, privateKey, hashOctets)
+	V = computeMAC(hashFunc, K, V)
+
+	//K = HMAC_K(V || 0x01 || int2octets(x) || bits2octets(h1)), V = HMAC_K(V)
+	K = computeMAC(hashFunc, K, V, []byte{0x01}, privateKey, hashOctets)
+	V = computeMAC(hashFunc, K, V)
+
+	return &nonceGenerator{hashFunc: hashFunc, N: N, K: K, V: V}
+}
+
+// next candidate k in [1, N - 1]
+func (g *nonceGenerator) next() *big.Int {
+	for {
+		if g.started {
+			//K = HMAC_K(V || 0x00), V = HMAC_K(V)
+			g.K = computeMAC(g.hashFunc, g.K, g.V, []byte{0x00})
+			g.V = computeMAC(g.hashFunc, g.K, g.V)
+		}
+
+		g.started = true
+
+		var T []byte
+
+		for len(T)*8 < g.N.BitLen() {
+			g.V = computeMAC(g.hashFunc, g.K, g.V)
+			T = append(T, g.V...)
+		}
+
+		k := bits2int(T, g.N)
+		if k.Sign() > 0 && k.Cmp(g.N) < 0 {
+			return k
+		}
+	}
+}
+
+func computeMAC(hashFunc func() hash.Hash, key []byte, data ...[]byte) []byte {
+	mac := hmac.New(hashFunc, key)
+	for _, part := range data {
+		mac.Write(part)
+	}
+
+	return mac.Sum(nil)
+}
+
+// bits2int takes leftmost bitlen(N) bits of input as integer, FIPS 186-4 converts message hash the same way,
+// so it is used both for nonce generation and for signing and verification
+func bits2int(input []byte, N *big.Int) *big.Int {
+	result := new(big.Int).SetBytes(input)
+
+	if excess := len(input)*8 - N.BitLen(); excess > 0 {
+		result.Rsh(result, uint(excess))
+	}
+
+	return result
+}
+
+// int2octets big endian representation of val with length of N in bytes
+func int2octets(val, N *big.Int) []byte {
+	return val.FillBytes(make([]byte, (N.BitLen()+7)/8))
+}
+
+func bits2octets(input []byte, N *big.Int) []byte {
+	z := bits2int(input, N)
+	if z.Cmp(N) >= 0 {
+		z.Sub(z, N)
+	}
+
+	return int2octets(z, N)
+}
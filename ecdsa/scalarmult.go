@@ -0,0 +1,255 @@
+package ecdsa
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"sync"
+)
+
+// windowSize scalar is processed by 4 bits, so table has 16 points
+const windowSize = 4
+
+// jacobianPoint (X, Y, Z) is (X/Z^2, Y/Z^3) in affine coordinates, Z = 0 is the point at infinity
+type jacobianPoint struct {
+	x, y, z fieldElement
+}
+
+// curveEngine constant time arithmetic for short Weierstrass curve y^2 = x^3 + a*x + b,
+// it is used for operations with secrets (private key and nonce), public data is still handled by elliptic.Curve
+type curveEngine struct {
+	fp *montgomeryField // coordinates, mod p
+	fn *montgomeryField // scalars, mod n
+	a  fieldElement
+	// baseTable 0*G, 1*G, ..., 15*G
+	baseTable [1 << windowSize]jacobianPoint
+	// scalarSize fixed length of scalar in bytes, all scalars are processed with the same amount of windows
+	scalarSize int
+}
+
+var engines sync.Map
+
+// engineFor returns cached engine for the curve
+func engineFor(curve elliptic.Curve) *curveEngine {
+	if engine, ok := engines.Load(curve); ok {
+		return engine.(*curveEngine)
+	}
+
+	engine, _ := engines.LoadOrStore(curve, newCurveEngine(curve))
+
+	return engine.(*curveEngine)
+}
+
+func newCurveEngine(curve elliptic.Curve) *curveEngine {
+	params := curve.Params()
+
+	e := &curveEngine{
+		fp:         newMontgomeryField(params.P),
+		fn:         newMontgomeryField(params.N),
+		scalarSize: (params.N.BitLen() + 7) / 8,
+	}
+	e.a = e.fp.fromBig(curveA(curve))
+
+	G := jacobianPoint{x: e.fp.fromBig(params.Gx), y: e.fp.fromBig(params.Gy), z: e.fp.one()}
+	e.baseTable = e.precompute(G)
+
+	return e
+}
+
+// scalarBaseMult k x G in constant time, the result is affine, (0, 0) is the point at infinity
+func scalarBaseMult(curve elliptic.Curve, k *big.Int) (*big.Int, *big.Int) {
+	e := engineFor(curve)
+
+	return e.toAffine(e.scalarMult(&e.baseTable, k))
+}
+
+// ScalarMult k x (x, y) in constant time for secret k (e.g. ECDH private key), the result is affine,
+// (0, 0) is the point at infinity. (x, y) is public, but it must be on curve, it is not checked here
+func ScalarMult(curve elliptic.Curve, x, y, k *big.Int) (*big.Int, *big.Int) {
+	e := engineFor(curve)
+
+	point := jacobianPoint{x: e.fp.fromBig(x), y: e.fp.fromBig(y), z: e.fp.one()}
+	table := e.precompute(point)
+
+	return e.toAffine(e.scalarMult(&table, k))
+}
+
+// signScalar s = k^-1 * (h + r*d) mod n in constant time
+func signScalar(curve elliptic.Curve, k, d, h, r *big.Int) *big.Int {
+	fn := engineFor(curve).fn
+
+	kInverse := fn.inverse(fn.fromBig(k))
+
+	s := fn.mul(fn.fromBig(r), fn.fromBig(d))
+	s = fn.add(s, fn.fromBig(h))
+	s = fn.mul(s, kInverse)
+
+	return fn.toBig(s)
+}
+
+func (e *curveEngine) precompute(point jacobianPoint) [1 << windowSize]jacobianPoint {
+	var table [1 << windowSize]jacobianPoint
+	table[0] = e.infinity()
+	table[1] = point
+
+	for i := 2; i < len(table); i++ {
+		table[i] = e.add(table[i-1], point)
+	}
+
+	return table
+}
+
+// scalarMult fixed window: 4 doublings and one addition for every 4 bits of fixed size scalar,
+// table entry is read with constant time lookup, so neither branches nor memory access depend on k
+func (e *curveEngine) scalarMult(table *[1 << windowSize]jacobianPoint, k *big.Int) jacobianPoint {
+	if k.Sign() < 0 || k.Cmp(e.fn.modulus) >= 0 {
+		k = new(big.Int).Mod(k, e.fn.modulus)
+	}
+
+	scalar := k.FillBytes(make([]byte, e.scalarSize))
+	result := e.infinity()
+
+	for _, b := range scalar {
+		for _, window := range [2]byte{b >> 4, b & 0x0f} {
+			for i := 0; i < windowSize; i++ {
+				result = e.double(result)
+			}
+
+			result = e.add(result, e.lookup(table, uint64(window)))
+		}
+	}
+
+	return result
+}
+
+// lookup reads every entry of table and keeps the one with required index
+func (e *curveEngine) lookup(table *[1 << windowSize]jacobianPoint, index uint64) jacobianPoint {
+	result := e.infinity()
+
+	for i := range table {
+		flag := isEqual(uint64(i), index)
+
+		result.x = e.fp.selectElement(flag, table[i].x, result.x)
+		result.y = e.fp.selectElement(flag, table[i].y, result.y)
+		result.z = e.fp.selectElement(flag, table[i].z, result.z)
+	}
+
+	return result
+}
+
+func (e *curveEngine) infinity() jacobianPoint {
+	return jacobianPoint{x: e.fp.one(), y: e.fp.one()}
+}
+
+// double dbl-2007-bl, works for any a, the point at infinity stays at infinity (Z3 = 0)
+func (e *curveEngine) double(point jacobianPoint) jacobianPoint {
+	f := e.fp
+
+	xx := f.square(point.x)
+	yy := f.square(point.y)
+	yyyy := f.square(yy)
+	zz := f.square(point.z)
+
+	//S = 2*((X1 + YY)^2 - XX - YYYY)
+	s := f.sub(f.sub(f.square(f.add(point.x, yy)), xx), yyyy)
+	s = f.add(s, s)
+
+	//M = 3*XX + a*ZZ^2
+	m := f.add(f.add(xx, xx), xx)
+	m = f.add(m, f.mul(e.a, f.square(zz)))
+
+	//X3 = M^2 - 2*S
+	x3 := f.sub(f.square(m), f.add(s, s))
+
+	//Y3 = M*(S - X3) - 8*YYYY
+	yyyy8 := f.add(yyyy, yyyy)
+	yyyy8 = f.add(yyyy8, yyyy8)
+	yyyy8 = f.add(yyyy8, yyyy8)
+	y3 := f.sub(f.mul(m, f.sub(s, x3)), yyyy8)
+
+	//Z3 = (Y1 + Z1)^2 - YY - ZZ
+	z3 := f.sub(f.sub(f.square(f.add(point.y, point.z)), yy), zz)
+
+	return jacobianPoint{x: x3, y: y3, z: z3}
+}
+
+// add add-2007-bl, special cases (one of points is infinity, points are equal) are handled with constant time
+// selection, so doubling is always computed as well
+func (e *curveEngine) add(p1, p2 jacobianPoint) jacobianPoint {
+	f := e.fp
+
+	z1z1 := f.square(p1.z)
+	z2z2 := f.square(p2.z)
+
+	//U1 = X1*Z2Z2, U2 = X2*Z1Z1
+	u1 := f.mul(p1.x, z2z2)
+	u2 := f.mul(p2.x, z1z1)
+
+	//S1 = Y1*Z2*Z2Z2, S2 = Y2*Z1*Z1Z1
+	s1 := f.mul(f.mul(p1.y, p2.z), z2z2)
+	s2 := f.mul(f.mul(p2.y, p1.z), z1z1)
+
+	//H = U2 - U1, I = (2*H)^2, J = H*I
+	h := f.sub(u2, u1)
+	i := f.square(f.add(h, h))
+	j := f.mul(h, i)
+
+	//r = 2*(S2 - S1), V = U1*I
+	r := f.sub(s2, s1)
+	r = f.add(r, r)
+	v := f.mul(u1, i)
+
+	//X3 = r^2 - J - 2*V
+	x3 := f.sub(f.sub(f.square(r), j), f.add(v, v))
+
+	//Y3 = r*(V - X3) - 2*S1*J
+	s1j := f.mul(s1, j)
+	y3 := f.sub(f.mul(r, f.sub(v, x3)), f.add(s1j, s1j))
+
+	//Z3 = ((Z1 + Z2)^2 - Z1Z1 - Z2Z2)*H
+	z3 := f.mul(f.sub(f.sub(f.square(f.add(p1.z, p2.z)), z1z1), z2z2), h)
+
+	result := jacobianPoint{x: x3, y: y3, z: z3}
+
+	// P1 = P2, formulas give zero, so doubling is used
+	doubled := e.double(p1)
+	isDouble := f.isZero(h) & f.isZero(r) & (f.isZero(p1.z) ^ 1) & (f.isZero(p2.z) ^ 1)
+	result = e.selectPoint(isDouble, doubled, result)
+
+	// infinity + P2 = P2, P1 + infinity = P1
+	result = e.selectPoint(f.isZero(p1.z), p2, result)
+	result = e.selectPoint(f.isZero(p2.z), p1, result)
+
+	return result
+}
+
+func (e *curveEngine) selectPoint(flag uint64, p1, p2 jacobianPoint) jacobianPoint {
+	return jacobianPoint{
+		x: e.fp.selectElement(flag, p1.x, p2.x),
+		y: e.fp.selectElement(flag, p1.y, p2.y),
+		z: e.fp.selectElement(flag, p1.z, p2.z),
+	}
+}
+
+// toAffine x = X/Z^2, y = Y/Z^3, the point at infinity is (0, 0)
+func (e *curveEngine) toAffine(point jacobianPoint) (*big.Int, *big.Int) {
+	f := e.fp
+
+	if f.isZero(point.z) == 1 {
+		return new(big.Int), new(big.Int)
+	}
+
+	zInverse := f.inverse(point.z)
+	zInverse2 := f.square(zInverse)
+
+	x := f.mul(point.x, zInverse2)
+	y := f.mul(point.y, f.mul(zInverse2, zInverse))
+
+	return f.toBig(x), f.toBig(y)
+}
+
+// isEqual 1 if a = b, otherwise 0
+func isEqual(a, b uint64) uint64 {
+	x := a ^ b
+
+	return ((x | -x) >> 63) ^ 1
+}
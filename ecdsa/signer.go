@@ -0,0 +1,105 @@
+package ecdsa
+
+import (
+	"crypto"
+	stdecdsa "crypto/ecdsa"
+	"io"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrUnknownCurve      = errors.New("key curve is not set")
+	ErrInvalidDigestSize = errors.New("digest size does not match hash function")
+	ErrFailedToGenerateK = errors.New("failed to generate nonce")
+)
+
+// Public implements crypto.Signer, key is returned as *crypto/ecdsa.PublicKey,
+// so crypto/x509, crypto/tls and others can work with it
+func (key *PrivateKey) Public() crypto.PublicKey {
+	if key.PK.Curve == nil {
+		return nil
+	}
+
+	return key.PK.ToECDSA()
+}
+
+// Sign implements crypto.Signer: digest is signed with nonce from rand, result is DER encoded signature
+func (key *PrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	curve := key.PK.Curve
+	if curve == nil {
+		return nil, ErrUnknownCurve
+	}
+
+	if opts != nil && opts.HashFunc() != 0 && len(digest) != opts.HashFunc().Size() {
+		return nil, ErrInvalidDigestSize
+	}
+
+	for {
+		k, err := randomK(rand, curve.Params().N)
+		if err != nil {
+			return nil, err
+		}
+
+		// k giving r = 0 or s = 0 is replaced with the next one
+		sig, err := SignDigest(curve, digest, key.D, k)
+		if err == ErrInvalidNonce {
+			continue
+		}
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to sign digest")
+		}
+
+		return sig.MarshalASN1()
+	}
+}
+
+// randomK k <= random ∈ [1, n - 1] from any source of randomness
+func randomK(rand io.Reader, N *big.Int) (*big.Int, error) {
+	buf := make([]byte, (N.BitLen()+7)/8)
+
+	for i := 0; i < 100; i++ {
+		if _, err := io.ReadFull(rand, buf); err != nil {
+			return nil, errors.Wrap(err, "failed to read random bytes")
+		}
+
+		k := bits2int(buf, N)
+		if k.Sign() > 0 && k.Cmp(N) < 0 {
+			return k, nil
+		}
+	}
+
+	return nil, ErrFailedToGenerateK
+}
+
+func (pk *PublicKey) ToECDSA() *stdecdsa.PublicKey {
+	return &stdecdsa.PublicKey{
+		Curve: pk.Curve,
+		X:     new(big.Int).Set(pk.X),
+		Y:     new(big.Int).Set(pk.Y),
+	}
+}
+
+func (key *PrivateKey) ToECDSA() *stdecdsa.PrivateKey {
+	return &stdecdsa.PrivateKey{
+		PublicKey: *key.PK.ToECDSA(),
+		D:         new(big.Int).Set(key.D),
+	}
+}
+
+func FromECDSAPublicKey(pk *stdecdsa.PublicKey) *PublicKey {
+	return &PublicKey{
+		Curve: pk.Curve,
+		X:     new(big.Int).Set(pk.X),
+		Y:     new(big.Int).Set(pk.Y),
+	}
+}
+
+func FromECDSA(key *stdecdsa.PrivateKey) *PrivateKey {
+	return &PrivateKey{
+		PK: *FromECDSAPublicKey(&key.PublicKey),
+		D:  new(big.Int).Set(key.D),
+	}
+}
@@ -0,0 +1,179 @@
+package ed25519
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/mhrynenko/cryptography_course/sha512"
+	"github.com/pkg/errors"
+)
+
+const (
+	SeedSize      = 32
+	PublicKeySize = 32
+	SignatureSize = 64
+)
+
+var (
+	ErrInvalidSeed              = errors.New("seed must be 32 bytes")
+	ErrInvalidPrivateKey        = errors.New("private key is invalid")
+	ErrInvalidPublicKey         = errors.New("public key is invalid")
+	ErrInvalidSignatureEncoding = errors.New("signature encoding is invalid")
+	ErrNumberIsOutOfRange       = errors.New("number is out of range")
+)
+
+// L = 2^252 + 27742317777372353535851937790883648493, order of the base point
+var L = func() *big.Int {
+	result, _ := new(big.Int).SetString("27742317777372353535851937790883648493", 10)
+	return result.Add(result, new(big.Int).Lsh(big.NewInt(1), 252))
+}()
+
+type PublicKey struct {
+	Point
+}
+
+// PrivateKey D is clamped secret scalar, prefix is the second half of SHA512(seed) used for nonce derivation
+type PrivateKey struct {
+	PK     PublicKey
+	D      *big.Int
+	Seed   []byte
+	prefix []byte
+}
+
+// Signature R is nonce point, S = r + k*D mod L
+type Signature struct {
+	R Point
+	S *big.Int
+}
+
+func GeneratePrivateKey() (*PrivateKey, error) {
+	seed := make([]byte, SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, errors.Wrap(err, "failed to generate random seed")
+	}
+
+	return NewKeyFromSeed(seed)
+}
+
+// NewKeyFromSeed h = SHA512(seed), the first half of h is clamped to get secret scalar (RFC 8032 5.1.5)
+func NewKeyFromSeed(seed []byte) (*PrivateKey, error) {
+	if len(seed) != SeedSize {
+		return nil, ErrInvalidSeed
+	}
+
+	h := sha512.Compute(seed)
+
+	//clear 3 lowest bits (multiple of cofactor 8), clear the highest bit and set the second highest one
+	scalar := make([]byte, 32)
+	copy(scalar, h[:32])
+	scalar[0] &= 248
+	scalar[31] &= 127
+	scalar[31] |= 64
+
+	D := new(big.Int).SetBytes(reverse(scalar))
+
+	return &PrivateKey{
+		PK:     PublicKey{Point: scalarBaseMult(D).toAffine()},
+		D:      D,
+		Seed:   append([]byte(nil), seed...),
+		prefix: append([]byte(nil), h[32:]...),
+	}, nil
+}
+
+// ParsePublicKey decodes 32-byte public key
+func ParsePublicKey(data []byte) (*PublicKey, error) {
+	point, err := ParsePoint(data)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidPublicKey, err.Error())
+	}
+
+	return &PublicKey{Point: *point}, nil
+}
+
+// Sign is deterministic: nonce r = SHA512(prefix || msg), so the same message always gives the same signature
+func Sign(key *PrivateKey, msg []byte) (*Signature, error) {
+	if key == nil || key.D == nil || len(key.prefix) != 32 {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	//r = SHA512(prefix || M) mod L
+	r := hashToScalar(key.prefix, msg)
+
+	//R = r x B
+	R := scalarBaseMult(r).toAffine()
+
+	//k = SHA512(R || A || M) mod L
+	k := hashToScalar(R.Bytes(), key.PK.Bytes(), msg)
+
+	//S = (r + k*D) mod L
+	S := new(big.Int).Mul(k, key.D)
+	S.Add(S, r)
+	S.Mod(S, L)
+
+	return &Signature{R: R, S: S}, nil
+}
+
+// Verify cofactored verification: [8][S]B = [8]R + [8][k]A
+func Verify(pk PublicKey, msg []byte, sig *Signature) (bool, error) {
+	if pk.X == nil || pk.Y == nil {
+		return false, ErrInvalidPublicKey
+	}
+
+	if sig == nil || sig.R.X == nil || sig.R.Y == nil || sig.S == nil {
+		return false, ErrInvalidSignatureEncoding
+	}
+
+	//0 <= S < L
+	if sig.S.Sign() < 0 || sig.S.Cmp(L) >= 0 {
+		return false, ErrNumberIsOutOfRange
+	}
+
+	//k = SHA512(R || A || M) mod L
+	k := hashToScalar(sig.R.Bytes(), pk.Bytes(), msg)
+
+	//[S]B - R - [k]A
+	result := toExtended(basePoint).scalarMult(sig.S)
+	result = result.add(toExtended(sig.R).negate())
+	result = result.add(toExtended(pk.Point).scalarMult(k).negate())
+
+	//multiplying by cofactor removes small order components
+	result = result.double().double().double()
+
+	return result.isIdentity(), nil
+}
+
+// Bytes R || S, S is 32-byte little endian
+func (sig *Signature) Bytes() []byte {
+	return append(sig.R.Bytes(), reverse(sig.S.FillBytes(make([]byte, 32)))...)
+}
+
+// ParseSignature decodes R and checks that S < L
+func ParseSignature(data []byte) (*Signature, error) {
+	if len(data) != SignatureSize {
+		return nil, ErrInvalidSignatureEncoding
+	}
+
+	R, err := ParsePoint(data[:32])
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidSignatureEncoding, err.Error())
+	}
+
+	S := new(big.Int).SetBytes(reverse(data[32:]))
+	if S.Cmp(L) >= 0 {
+		return nil, ErrNumberIsOutOfRange
+	}
+
+	return &Signature{R: *R, S: S}, nil
+}
+
+// hashToScalar SHA512 of concatenated data as little endian number mod L
+func hashToScalar(data ...[]byte) *big.Int {
+	h := sha512.New()
+	for _, d := range data {
+		h.Write(d)
+	}
+
+	result := new(big.Int).SetBytes(reverse(h.Sum(nil)))
+
+	return result.Mod(result, L)
+}
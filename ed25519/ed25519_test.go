@@ -0,0 +1,285 @@
+package ed25519
+
+import (
+	stded25519 "crypto/ed25519"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Vector struct {
+	seed      string
+	publicKey string
+	msg       string
+	signature string
+}
+
+// vectors from RFC 8032 7.1
+var vectors = []Vector{
+	{
+		seed:      "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+		publicKey: "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		msg:       "",
+		signature: "e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b",
+	},
+	{
+		seed:      "4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+		publicKey: "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+		msg:       "72",
+		signature: "92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00",
+	},
+	{
+		seed:      "c5aa8df43f9f837bedb7442f31dcb7b166d38535076f094b85ce3a2e0b4458f7",
+		publicKey: "fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+		msg:       "af82",
+		signature: "6291d657deec24024827e69c3abe01a30ce548a284743a445e3680d7db5ac3ac18ff9b538d16f290ae67f760984dc6594a7c15e9716ed28dc027beceea1ec40a",
+	},
+	{
+		// SHA(abc)
+		seed:      "833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42",
+		publicKey: "ec172b93ad5e563bf4932c70e1245034c35467ef2efd4d64ebf819683467e2bf",
+		msg:       "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+		signature: "dc2a4459e7369633a52b1bf277839a00201009a3efbf3ecb69bea2186c26b58909351fc9ac90b3ecfdfbc7c66431e0303dca179c138ac17ad9bef1177331a704",
+	},
+}
+
+func TestEd25519(t *testing.T) {
+	for i, vector := range vectors {
+		key, err := NewKeyFromSeed(common.Hex2Bytes(vector.seed))
+		if err != nil {
+			t.Fatalf("ed25519.NewKeyFromSeed: unexcpected error `%s`", err.Error())
+		}
+
+		if common.Bytes2Hex(key.PK.Bytes()) != vector.publicKey {
+			t.Errorf("ed25519.NewKeyFromSeed: wrong public key for %d vector", i)
+		}
+
+		msg := common.Hex2Bytes(vector.msg)
+
+		sig, err := Sign(key, msg)
+		if err != nil {
+			t.Fatalf("ed25519.Sign: unexcpected error `%s`", err.Error())
+		}
+
+		if common.Bytes2Hex(sig.Bytes()) != vector.signature {
+			t.Errorf("ed25519.Sign: wrong signature for %d vector", i)
+		}
+
+		pk, err := ParsePublicKey(common.Hex2Bytes(vector.publicKey))
+		if err != nil {
+			t.Fatalf("ed25519.ParsePublicKey: unexcpected error `%s`", err.Error())
+		}
+
+		parsed, err := ParseSignature(common.Hex2Bytes(vector.signature))
+		if err != nil {
+			t.Fatalf("ed25519.ParseSignature: unexcpected error `%s`", err.Error())
+		}
+
+		isVerified, err := Verify(*pk, msg, parsed)
+		if err != nil || !isVerified {
+			t.Errorf("ed25519.Verify: valid signature is rejected for %d vector, err = %v", i, err)
+		}
+
+		isVerified, err = Verify(*pk, append(msg, 1), parsed)
+		if err != nil || isVerified {
+			t.Errorf("ed25519.Verify: signature of another message is accepted for %d vector, err = %v", i, err)
+		}
+	}
+}
+
+func TestStdlibInterop(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		key, err := GeneratePrivateKey()
+		if err != nil {
+			t.Fatalf("ed25519.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		stdKey := stded25519.NewKeyFromSeed(key.Seed)
+		stdPub := stdKey.Public().(stded25519.PublicKey)
+
+		if common.Bytes2Hex(key.PK.Bytes()) != common.Bytes2Hex(stdPub) {
+			t.Errorf("ed25519.NewKeyFromSeed: public key does not match crypto/ed25519 for %d vector", i)
+		}
+
+		msg := make([]byte, i*7)
+		if _, err := rand.Read(msg); err != nil {
+			t.Fatalf("rand.Read: unexcpected error `%s`", err.Error())
+		}
+
+		sig, err := Sign(key, msg)
+		if err != nil {
+			t.Fatalf("ed25519.Sign: unexcpected error `%s`", err.Error())
+		}
+
+		stdSig := stded25519.Sign(stdKey, msg)
+		if common.Bytes2Hex(sig.Bytes()) != common.Bytes2Hex(stdSig) {
+			t.Errorf("ed25519.Sign: signature does not match crypto/ed25519 for %d vector", i)
+		}
+
+		if !stded25519.Verify(stdPub, msg, sig.Bytes()) {
+			t.Errorf("ed25519.Verify: crypto/ed25519 rejects signature for %d vector", i)
+		}
+
+		parsed, err := ParseSignature(stdSig)
+		if err != nil {
+			t.Fatalf("ed25519.ParseSignature: unexcpected error `%s`", err.Error())
+		}
+
+		isVerified, err := Verify(key.PK, msg, parsed)
+		if err != nil || !isVerified {
+			t.Errorf("ed25519.Verify: crypto/ed25519 signature is rejected for %d vector, err = %v", i, err)
+		}
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	key, err := NewKeyFromSeed(common.Hex2Bytes(vectors[0].seed))
+	if err != nil {
+		t.Fatalf("ed25519.NewKeyFromSeed: unexcpected error `%s`", err.Error())
+	}
+
+	if _, err := NewKeyFromSeed(make([]byte, 31)); err != ErrInvalidSeed {
+		t.Errorf("ed25519.NewKeyFromSeed: expected error for short seed")
+	}
+
+	// y = p is not reduced
+	notReduced := reverse(p.FillBytes(make([]byte, 32)))
+	if _, err := ParsePublicKey(notReduced); err == nil {
+		t.Errorf("ed25519.ParsePublicKey: expected error for y >= p")
+	}
+
+	// y = 2 has no x
+	if _, err := ParsePublicKey(encodeY(big.NewInt(2), 0)); err == nil {
+		t.Errorf("ed25519.ParsePublicKey: expected error for point not on curve")
+	}
+
+	// x = 0 with sign bit set
+	if _, err := ParsePublicKey(encodeY(big.NewInt(1), 1)); err == nil {
+		t.Errorf("ed25519.ParsePublicKey: expected error for negative zero")
+	}
+
+	// S + L is the same number mod L, but it must be rejected to avoid malleability
+	sig := common.Hex2Bytes(vectors[0].signature)
+	S := new(big.Int).SetBytes(reverse(sig[32:]))
+	S.Add(S, L)
+	copy(sig[32:], reverse(S.FillBytes(make([]byte, 32))))
+
+	if _, err := ParseSignature(sig); err != ErrNumberIsOutOfRange {
+		t.Errorf("ed25519.ParseSignature: expected error for S >= L")
+	}
+
+	parsed, err := ParseSignature(common.Hex2Bytes(vectors[0].signature))
+	if err != nil {
+		t.Fatalf("ed25519.ParseSignature: unexcpected error `%s`", err.Error())
+	}
+
+	if _, err := Verify(key.PK, nil, &Signature{R: parsed.R, S: S}); err != ErrNumberIsOutOfRange {
+		t.Errorf("ed25519.Verify: expected error for S >= L")
+	}
+
+	if _, err := Verify(PublicKey{}, nil, parsed); err != ErrInvalidPublicKey {
+		t.Errorf("ed25519.Verify: expected error for empty public key")
+	}
+
+	if _, err := Verify(key.PK, nil, nil); err != ErrInvalidSignatureEncoding {
+		t.Errorf("ed25519.Verify: expected error for nil signature")
+	}
+}
+
+func TestGroupLaw(t *testing.T) {
+	// L x B is the neutral element
+	if !scalarBaseMult(L).isIdentity() {
+		t.Errorf("ed25519: L x B is not the neutral element")
+	}
+
+	// (a + b) x B = a x B + b x B
+	a, b := big.NewInt(123456789), new(big.Int).Sub(L, big.NewInt(987654321))
+	sum := new(big.Int).Add(a, b)
+
+	left := scalarBaseMult(sum).toAffine()
+	right := scalarBaseMult(a).add(scalarBaseMult(b)).toAffine()
+
+	if left.X.Cmp(right.X) != 0 || left.Y.Cmp(right.Y) != 0 {
+		t.Errorf("ed25519: (a + b) x B != a x B + b x B")
+	}
+}
+
+// TestScalarBaseMult constant time multiplication gives the same points as double-and-add
+func TestScalarBaseMult(t *testing.T) {
+	maxScalar := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(15),
+		big.NewInt(16),
+		new(big.Int).Sub(L, big.NewInt(1)),
+		new(big.Int).Add(L, big.NewInt(1)),
+		maxScalar,
+		new(big.Int).Lsh(maxScalar, 1),
+		big.NewInt(-5),
+	}
+
+	for i := 0; i < 8; i++ {
+		k, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 255))
+		if err != nil {
+			t.Fatalf("rand.Int: unexcpected error `%s`", err.Error())
+		}
+
+		scalars = append(scalars, k)
+	}
+
+	for i, k := range scalars {
+		expected := toExtended(basePoint).scalarMult(new(big.Int).Mod(k, L)).toAffine()
+		got := scalarBaseMult(k).toAffine()
+
+		if got.X.Cmp(expected.X) != 0 || got.Y.Cmp(expected.Y) != 0 {
+			t.Errorf("ed25519.scalarBaseMult: wrong point for %d vector", i)
+		}
+	}
+}
+
+// TestFieldElement fixed-limb arithmetic matches big.Int one, edge values p - 1 and p - 2 are included
+func TestFieldElement(t *testing.T) {
+	values := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(19),
+		new(big.Int).Sub(p, big.NewInt(1)),
+		new(big.Int).Sub(p, big.NewInt(2)),
+		new(big.Int).Lsh(big.NewInt(1), 254),
+	}
+
+	for i := 0; i < 8; i++ {
+		x, err := rand.Int(rand.Reader, p)
+		if err != nil {
+			t.Fatalf("rand.Int: unexcpected error `%s`", err.Error())
+		}
+
+		values = append(values, x)
+	}
+
+	for i, a := range values {
+		for j, b := range values {
+			fa, fb := newFieldElement(a), newFieldElement(b)
+
+			if fa.add(fb).toBig().Cmp(feAdd(a, b)) != 0 {
+				t.Errorf("ed25519.fieldElement.add: wrong result for %d, %d vector", i, j)
+			}
+
+			if fa.sub(fb).toBig().Cmp(feSub(a, b)) != 0 {
+				t.Errorf("ed25519.fieldElement.sub: wrong result for %d, %d vector", i, j)
+			}
+
+			if fa.mul(fb).toBig().Cmp(feMul(a, b)) != 0 {
+				t.Errorf("ed25519.fieldElement.mul: wrong result for %d, %d vector", i, j)
+			}
+		}
+
+		if newFieldElement(a).inverse().toBig().Cmp(feInv(a)) != 0 {
+			t.Errorf("ed25519.fieldElement.inverse: wrong result for %d vector", i)
+		}
+	}
+}
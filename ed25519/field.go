@@ -0,0 +1,67 @@
+package ed25519
+
+import "math/big"
+
+// p = 2^255 - 19
+var p = func() *big.Int {
+	result := new(big.Int).Lsh(big.NewInt(1), 255)
+	return result.Sub(result, big.NewInt(19))
+}()
+
+// d = -121665/121666 mod p, parameter of curve -x^2 + y^2 = 1 + d*x^2*y^2
+var d = func() *big.Int {
+	result := feMul(big.NewInt(-121665), feInv(big.NewInt(121666)))
+	return result
+}()
+
+// d2 = 2*d, used in point addition
+var d2 = feMul(d, big.NewInt(2))
+
+// sqrtM1 = 2^((p - 1) / 4) mod p, square root of -1
+var sqrtM1 = func() *big.Int {
+	exp := new(big.Int).Sub(p, big.NewInt(1))
+	exp.Rsh(exp, 2)
+
+	return new(big.Int).Exp(big.NewInt(2), exp, p)
+}()
+
+func feAdd(a, b *big.Int) *big.Int {
+	result := new(big.Int).Add(a, b)
+	return result.Mod(result, p)
+}
+
+func feSub(a, b *big.Int) *big.Int {
+	result := new(big.Int).Sub(a, b)
+	return result.Mod(result, p)
+}
+
+func feMul(a, b *big.Int) *big.Int {
+	result := new(big.Int).Mul(a, b)
+	return result.Mod(result, p)
+}
+
+// feInv a^(p - 2) mod p, zero has no inverse and stays zero
+func feInv(a *big.Int) *big.Int {
+	return new(big.Int).Exp(new(big.Int).Mod(a, p), new(big.Int).Sub(p, big.NewInt(2)), p)
+}
+
+// feSqrtRatio finds x with x^2 = u/v, candidate x = (u/v)^((p + 3) / 8) is multiplied by sqrt(-1) if it is
+// the root of -u/v (RFC 8032 5.1.3)
+func feSqrtRatio(u, v *big.Int) (*big.Int, bool) {
+	ratio := feMul(u, feInv(v))
+
+	exp := new(big.Int).Add(p, big.NewInt(3))
+	exp.Rsh(exp, 3)
+	x := new(big.Int).Exp(ratio, exp, p)
+
+	if feMul(x, x).Cmp(ratio) == 0 {
+		return x, true
+	}
+
+	x = feMul(x, sqrtM1)
+	if feMul(x, x).Cmp(ratio) == 0 {
+		return x, true
+	}
+
+	return nil, false
+}
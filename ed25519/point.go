@@ -0,0 +1,177 @@
+package ed25519
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidPointEncoding = errors.New("point encoding is invalid")
+
+// Point affine point on edwards25519
+type Point struct {
+	X *big.Int
+	Y *big.Int
+}
+
+// extendedPoint (X:Y:Z:T), x = X/Z, y = Y/Z, x*y = T/Z, formulas with these coordinates are complete,
+// so the same code works for doubling and for the neutral element
+type extendedPoint struct {
+	x, y, z, t *big.Int
+}
+
+// basePoint y = 4/5, x is even
+var basePoint = func() Point {
+	y := feMul(big.NewInt(4), feInv(big.NewInt(5)))
+
+	point, err := decodePoint(encodeY(y, 0))
+	if err != nil {
+		panic("invalid base point")
+	}
+
+	return *point
+}()
+
+func identity() extendedPoint {
+	return extendedPoint{x: big.NewInt(0), y: big.NewInt(1), z: big.NewInt(1), t: big.NewInt(0)}
+}
+
+func toExtended(point Point) extendedPoint {
+	return extendedPoint{
+		x: new(big.Int).Set(point.X),
+		y: new(big.Int).Set(point.Y),
+		z: big.NewInt(1),
+		t: feMul(point.X, point.Y),
+	}
+}
+
+func (point extendedPoint) toAffine() Point {
+	zInv := feInv(point.z)
+
+	return Point{X: feMul(point.x, zInv), Y: feMul(point.y, zInv)}
+}
+
+// isIdentity (0, 1) in affine coordinates
+func (point extendedPoint) isIdentity() bool {
+	return point.x.Sign() == 0 && point.y.Cmp(point.z) == 0
+}
+
+// add RFC 8032 5.1.4
+func (point extendedPoint) add(other extendedPoint) extendedPoint {
+	//A = (Y1 - X1)*(Y2 - X2), B = (Y1 + X1)*(Y2 + X2)
+	a := feMul(feSub(point.y, point.x), feSub(other.y, other.x))
+	b := feMul(feAdd(point.y, point.x), feAdd(other.y, other.x))
+	//C = T1*2*d*T2, D = Z1*2*Z2
+	c := feMul(feMul(point.t, d2), other.t)
+	dd := feMul(feMul(point.z, big.NewInt(2)), other.z)
+	//E = B - A, F = D - C, G = D + C, H = B + A
+	e := feSub(b, a)
+	f := feSub(dd, c)
+	g := feAdd(dd, c)
+	h := feAdd(b, a)
+
+	return extendedPoint{x: feMul(e, f), y: feMul(g, h), z: feMul(f, g), t: feMul(e, h)}
+}
+
+// double RFC 8032 5.1.4
+func (point extendedPoint) double() extendedPoint {
+	//A = X1^2, B = Y1^2, C = 2*Z1^2, H = A + B
+	a := feMul(point.x, point.x)
+	b := feMul(point.y, point.y)
+	c := feMul(big.NewInt(2), feMul(point.z, point.z))
+	h := feAdd(a, b)
+	//E = H - (X1 + Y1)^2, G = A - B, F = C + G
+	sum := feAdd(point.x, point.y)
+	e := feSub(h, feMul(sum, sum))
+	g := feSub(a, b)
+	f := feAdd(c, g)
+
+	return extendedPoint{x: feMul(e, f), y: feMul(g, h), z: feMul(f, g), t: feMul(e, h)}
+}
+
+func (point extendedPoint) negate() extendedPoint {
+	return extendedPoint{
+		x: feSub(big.NewInt(0), point.x),
+		y: new(big.Int).Set(point.y),
+		z: new(big.Int).Set(point.z),
+		t: feSub(big.NewInt(0), point.t),
+	}
+}
+
+// scalarMult k*point with double-and-add, it is variable time, so it is used only for verification with public values
+func (point extendedPoint) scalarMult(k *big.Int) extendedPoint {
+	result := identity()
+
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = result.double()
+
+		if k.Bit(i) == 1 {
+			result = result.add(point)
+		}
+	}
+
+	return result
+}
+
+// Bytes 32-byte little endian Y, the highest bit is the lowest bit of X (RFC 8032 5.1.2)
+func (point Point) Bytes() []byte {
+	return encodeY(point.Y, point.X.Bit(0))
+}
+
+// ParsePoint decodes point and checks that it is on curve (RFC 8032 5.1.3)
+func ParsePoint(data []byte) (*Point, error) {
+	if len(data) != 32 {
+		return nil, ErrInvalidPointEncoding
+	}
+
+	return decodePoint(data)
+}
+
+func encodeY(y *big.Int, xBit uint) []byte {
+	result := reverse(y.FillBytes(make([]byte, 32)))
+	result[31] |= byte(xBit << 7)
+
+	return result
+}
+
+func decodePoint(data []byte) (*Point, error) {
+	encoded := reverse(data)
+	xBit := encoded[0] >> 7
+	encoded[0] &= 0x7f
+
+	y := new(big.Int).SetBytes(encoded)
+	if y.Cmp(p) >= 0 {
+		return nil, ErrInvalidPointEncoding
+	}
+
+	//x^2 = (y^2 - 1) / (d*y^2 + 1)
+	yy := feMul(y, y)
+	u := feSub(yy, big.NewInt(1))
+	v := feAdd(feMul(d, yy), big.NewInt(1))
+
+	x, ok := feSqrtRatio(u, v)
+	if !ok {
+		return nil, ErrInvalidPointEncoding
+	}
+
+	// x = 0 has no pair with another sign
+	if x.Sign() == 0 && xBit == 1 {
+		return nil, ErrInvalidPointEncoding
+	}
+
+	if x.Bit(0) != uint(xBit) {
+		x.Sub(p, x)
+	}
+
+	return &Point{X: x, Y: y}, nil
+}
+
+// reverse returns reversed copy, RFC 8032 uses little endian numbers and big.Int big endian ones
+func reverse(data []byte) []byte {
+	result := make([]byte, len(data))
+	for i, b := range data {
+		result[len(data)-1-i] = b
+	}
+
+	return result
+}
@@ -0,0 +1,269 @@
+package ed25519
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// windowSize scalar is processed by 4 bits, so table has 16 points
+const windowSize = 4
+
+// fieldElement number mod p on four 64-bit little endian limbs, it is always fully reduced (< p),
+// every operation does the same amount of work for any values, so its timing does not depend on secrets
+type fieldElement [4]uint64
+
+// fieldP limbs of p = 2^255 - 19
+var fieldP = fieldElement{0xffffffffffffffed, 0xffffffffffffffff, 0xffffffffffffffff, 0x7fffffffffffffff}
+
+// fixedPoint extended point (X:Y:Z:T) with fixed-limb coordinates, it is used for multiplication by secret scalar
+type fixedPoint struct {
+	x, y, z, t fieldElement
+}
+
+// fixedD2 2*d as fixed-limb element
+var fixedD2 = newFieldElement(d2)
+
+// baseTable 0*B, 1*B, ..., 15*B
+var baseTable = func() [1 << windowSize]fixedPoint {
+	B := fixedPoint{
+		x: newFieldElement(basePoint.X),
+		y: newFieldElement(basePoint.Y),
+		z: newFieldElement(big.NewInt(1)),
+		t: newFieldElement(feMul(basePoint.X, basePoint.Y)),
+	}
+
+	var table [1 << windowSize]fixedPoint
+	table[0] = fixedIdentity()
+	table[1] = B
+
+	for i := 2; i < len(table); i++ {
+		table[i] = table[i-1].add(B)
+	}
+
+	return table
+}()
+
+// scalarBaseMult k x B in constant time for secret k (clamped private scalar or nonce),
+// the result has Z = 1, verification works with public values, so it uses variable time extendedPoint.scalarMult
+func scalarBaseMult(k *big.Int) extendedPoint {
+	if k.Sign() < 0 || k.BitLen() > 256 {
+		k = new(big.Int).Mod(k, L)
+	}
+
+	scalar := k.FillBytes(make([]byte, 32))
+	result := fixedIdentity()
+
+	for _, b := range scalar {
+		for _, window := range [2]byte{b >> 4, b & 0x0f} {
+			for i := 0; i < windowSize; i++ {
+				result = result.double()
+			}
+
+			result = result.add(lookup(&baseTable, uint64(window)))
+		}
+	}
+
+	return toExtended(result.toAffine())
+}
+
+// lookup reads every entry of table and keeps the one with required index
+func lookup(table *[1 << windowSize]fixedPoint, index uint64) fixedPoint {
+	result := fixedIdentity()
+
+	for i := range table {
+		flag := isEqual(uint64(i), index)
+
+		result.x = selectElement(flag, table[i].x, result.x)
+		result.y = selectElement(flag, table[i].y, result.y)
+		result.z = selectElement(flag, table[i].z, result.z)
+		result.t = selectElement(flag, table[i].t, result.t)
+	}
+
+	return result
+}
+
+func fixedIdentity() fixedPoint {
+	return fixedPoint{y: fieldElement{1}, z: fieldElement{1}}
+}
+
+// add RFC 8032 5.1.4, the same formulas as extendedPoint.add
+func (point fixedPoint) add(other fixedPoint) fixedPoint {
+	//A = (Y1 - X1)*(Y2 - X2), B = (Y1 + X1)*(Y2 + X2)
+	a := point.y.sub(point.x).mul(other.y.sub(other.x))
+	b := point.y.add(point.x).mul(other.y.add(other.x))
+	//C = T1*2*d*T2, D = Z1*2*Z2
+	c := point.t.mul(fixedD2).mul(other.t)
+	dd := point.z.add(point.z).mul(other.z)
+	//E = B - A, F = D - C, G = D + C, H = B + A
+	e := b.sub(a)
+	f := dd.sub(c)
+	g := dd.add(c)
+	h := b.add(a)
+
+	return fixedPoint{x: e.mul(f), y: g.mul(h), z: f.mul(g), t: e.mul(h)}
+}
+
+// double RFC 8032 5.1.4, the same formulas as extendedPoint.double
+func (point fixedPoint) double() fixedPoint {
+	//A = X1^2, B = Y1^2, C = 2*Z1^2, H = A + B
+	a := point.x.mul(point.x)
+	b := point.y.mul(point.y)
+	zz := point.z.mul(point.z)
+	c := zz.add(zz)
+	h := a.add(b)
+	//E = H - (X1 + Y1)^2, G = A - B, F = C + G
+	sum := point.x.add(point.y)
+	e := h.sub(sum.mul(sum))
+	g := a.sub(b)
+	f := c.add(g)
+
+	return fixedPoint{x: e.mul(f), y: g.mul(h), z: f.mul(g), t: e.mul(h)}
+}
+
+// toAffine x = X/Z, y = Y/Z, inversion is done on fixed limbs as well
+func (point fixedPoint) toAffine() Point {
+	zInv := point.z.inverse()
+
+	return Point{X: point.x.mul(zInv).toBig(), Y: point.y.mul(zInv).toBig()}
+}
+
+// newFieldElement x mod p split into limbs
+func newFieldElement(x *big.Int) fieldElement {
+	buf := new(big.Int).Mod(x, p).FillBytes(make([]byte, 32))
+
+	var result fieldElement
+	for i := range result {
+		result[i] = binary.BigEndian.Uint64(buf[8*(3-i):])
+	}
+
+	return result
+}
+
+func (a fieldElement) toBig() *big.Int {
+	buf := make([]byte, 32)
+	for i := range a {
+		binary.BigEndian.PutUint64(buf[8*(3-i):], a[i])
+	}
+
+	return new(big.Int).SetBytes(buf)
+}
+
+// reduce t - p if t is not less than p, t < 2p
+func (a fieldElement) reduce() fieldElement {
+	var u fieldElement
+	var borrow uint64
+
+	for i := range a {
+		u[i], borrow = bits.Sub64(a[i], fieldP[i], borrow)
+	}
+
+	return selectElement(borrow^1, u, a)
+}
+
+// add a + b < 2p < 2^256, so there is no carry
+func (a fieldElement) add(b fieldElement) fieldElement {
+	var t fieldElement
+	var carry uint64
+
+	for i := range a {
+		t[i], carry = bits.Add64(a[i], b[i], carry)
+	}
+
+	return t.reduce()
+}
+
+func (a fieldElement) sub(b fieldElement) fieldElement {
+	var t fieldElement
+	var borrow uint64
+
+	for i := range a {
+		t[i], borrow = bits.Sub64(a[i], b[i], borrow)
+	}
+
+	// add p back when a < b
+	mask := -borrow
+	var carry uint64
+	for i := range t {
+		t[i], carry = bits.Add64(t[i], fieldP[i]&mask, carry)
+	}
+
+	return t
+}
+
+// mul 512-bit product is folded with 2^256 = 38 mod p
+func (a fieldElement) mul(b fieldElement) fieldElement {
+	var t [8]uint64
+
+	for i := range a {
+		var c uint64
+		for j := range b {
+			hi, lo := bits.Mul64(a[i], b[j])
+			var cc uint64
+			lo, cc = bits.Add64(lo, t[i+j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[i+j], c = lo, hi
+		}
+		t[i+4] = c
+	}
+
+	//r = low + 38*high, the carry limb c is less than 39
+	var r fieldElement
+	var c uint64
+	for i := range r {
+		hi, lo := bits.Mul64(t[i+4], 38)
+		var cc uint64
+		lo, cc = bits.Add64(lo, c, 0)
+		hi += cc
+		r[i], cc = bits.Add64(t[i], lo, 0)
+		c = hi + cc
+	}
+
+	//r = r + 38*c, overflow leaves small r, so the second fold can not overflow
+	var carry uint64
+	r[0], carry = bits.Add64(r[0], c*38, 0)
+	for i := 1; i < len(r); i++ {
+		r[i], carry = bits.Add64(r[i], 0, carry)
+	}
+	r[0] += carry * 38
+
+	// r < 2^256 = 2p + 38
+	return r.reduce().reduce()
+}
+
+// inverse a^(p - 2), exponent is public, so square-and-multiply on its bits does not leak a
+func (a fieldElement) inverse() fieldElement {
+	e := new(big.Int).Sub(p, big.NewInt(2))
+	result := fieldElement{1}
+
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		result = result.mul(result)
+
+		if e.Bit(i) == 1 {
+			result = result.mul(a)
+		}
+	}
+
+	return result
+}
+
+// selectElement a if flag is 1, b if flag is 0
+func selectElement(flag uint64, a, b fieldElement) fieldElement {
+	mask := -flag
+
+	var result fieldElement
+	for i := range result {
+		result[i] = (a[i] & mask) | (b[i] &^ mask)
+	}
+
+	return result
+}
+
+// isEqual 1 if a = b, otherwise 0
+func isEqual(a, b uint64) uint64 {
+	x := a ^ b
+
+	return ((x | -x) >> 63) ^ 1
+}
@@ -0,0 +1,98 @@
+package hmac
+
+import (
+	"hash"
+)
+
+const (
+	ipad = 0x36
+	opad = 0x5c
+)
+
+// digest HMAC(K, m) = H((K' ^ opad) || H((K' ^ ipad) || m)), where K' is key padded to block size
+type digest struct {
+	inner hash.Hash
+	outer hash.Hash
+	ipad  []byte
+	opad  []byte
+}
+
+// New returns HMAC hash.Hash over any hash from this repo (e.g. sha256.New), key longer than block size is hashed first
+func New(h func() hash.Hash, key []byte) hash.Hash {
+	d := &digest{
+		inner: h(),
+		outer: h(),
+	}
+
+	blockSize := d.inner.BlockSize()
+
+	if len(key) > blockSize {
+		d.outer.Write(key)
+		key = d.outer.Sum(nil)
+		d.outer.Reset()
+	}
+
+	d.ipad = make([]byte, blockSize)
+	d.opad = make([]byte, blockSize)
+	copy(d.ipad, key)
+	copy(d.opad, key)
+
+	for i := 0; i < blockSize; i++ {
+		d.ipad[i] ^= ipad
+		d.opad[i] ^= opad
+	}
+
+	d.inner.Write(d.ipad)
+
+	return d
+}
+
+// Compute one-shot HMAC of msg
+func Compute(h func() hash.Hash, key, msg []byte) []byte {
+	mac := New(h, key)
+	mac.Write(msg)
+
+	return mac.Sum(nil)
+}
+
+func (d *digest) Reset() {
+	d.inner.Reset()
+	d.inner.Write(d.ipad)
+}
+
+func (d *digest) Size() int {
+	return d.outer.Size()
+}
+
+func (d *digest) BlockSize() int {
+	return d.inner.BlockSize()
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	return d.inner.Write(p)
+}
+
+// Sum appends current MAC to b, the state of d is not changed, so writing can be continued
+func (d *digest) Sum(b []byte) []byte {
+	innerHash := d.inner.Sum(nil)
+
+	d.outer.Reset()
+	d.outer.Write(d.opad)
+	d.outer.Write(innerHash)
+
+	return d.outer.Sum(b)
+}
+
+// Equal compares MACs in constant time, so comparison time does not leak position of the first different byte
+func Equal(mac1, mac2 []byte) bool {
+	if len(mac1) != len(mac2) {
+		return false
+	}
+
+	var diff byte
+	for i := range mac1 {
+		diff |= mac1[i] ^ mac2[i]
+	}
+
+	return diff == 0
+}
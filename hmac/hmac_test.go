@@ -0,0 +1,127 @@
+package hmac
+
+import (
+	"bytes"
+	"crypto/hmac"
+	stdsha256 "crypto/sha256"
+	stdsha512 "crypto/sha512"
+	"hash"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mhrynenko/cryptography_course/sha256"
+	"github.com/mhrynenko/cryptography_course/sha512"
+)
+
+type Vector struct {
+	key  []byte
+	data []byte
+	mac  string
+}
+
+// vectors from RFC 4231 for HMAC-SHA-256, in 5 test case result is truncated to 128 bits
+var vectors = []Vector{
+	{
+		key:  bytes.Repeat([]byte{0x0b}, 20),
+		data: []byte("Hi There"),
+		mac:  "b0344c61d8db38535ca8afceaf0bf12b881dc200c9833da726e9376c2e32cff7",
+	},
+	{
+		key:  []byte("Jefe"),
+		data: []byte("what do ya want for nothing?"),
+		mac:  "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+	},
+	{
+		key:  bytes.Repeat([]byte{0xaa}, 20),
+		data: bytes.Repeat([]byte{0xdd}, 50),
+		mac:  "773ea91e36800e46854db8ebd09181a72959098b3ef8c122d9635514ced565fe",
+	},
+	{
+		key:  common.Hex2Bytes("0102030405060708090a0b0c0d0e0f10111213141516171819"),
+		data: bytes.Repeat([]byte{0xcd}, 50),
+		mac:  "82558a389a443c0ea4cc819899f2083a85f0faa3e578f8077a2e3ff46729665b",
+	},
+	{
+		key:  bytes.Repeat([]byte{0x0c}, 20),
+		data: []byte("Test With Truncation"),
+		mac:  "a3b6167473100ee06e0c796c2955552b",
+	},
+	{
+		key:  bytes.Repeat([]byte{0xaa}, 131),
+		data: []byte("Test Using Larger Than Block-Size Key - Hash Key First"),
+		mac:  "60e431591ee0b67f0d8a26aacbf5b77f8e0bc6213728c5140546040f0ee37f54",
+	},
+	{
+		key:  bytes.Repeat([]byte{0xaa}, 131),
+		data: []byte("This is a test using a larger than block-size key and a larger than block-size data. The key needs to be hashed before being used by the HMAC algorithm."),
+		mac:  "9b09ffa71b942fcb27635fbcd5b0e944bfdc63644f0713938a7f51535c3a35e2",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	for i, vector := range vectors {
+		expected := common.Hex2Bytes(vector.mac)
+
+		local := Compute(sha256.New, vector.key, vector.data)
+		if bytes.Compare(local[:len(expected)], expected) != 0 {
+			t.Errorf("hmac.Compute: wrong result for `%d`, local = `0x%s`, expected = `0x%s`", i, common.Bytes2Hex(local), vector.mac)
+		}
+	}
+}
+
+func TestLib(t *testing.T) {
+	hashes := map[string][2]func() hash.Hash{
+		"sha256": {sha256.New, stdsha256.New},
+		"sha224": {sha256.New224, stdsha256.New224},
+		"sha384": {sha512.New384, stdsha512.New384},
+		"sha512": {sha512.New, stdsha512.New},
+	}
+
+	for name, h := range hashes {
+		for i, vector := range vectors {
+			local := New(h[0], vector.key)
+			lib := hmac.New(h[1], vector.key)
+
+			local.Write(vector.data)
+			lib.Write(vector.data)
+
+			if bytes.Compare(local.Sum(nil), lib.Sum(nil)) != 0 {
+				t.Errorf("hmac.New: wrong result for %s `%d`, local = `0x%s`, lib = `0x%s`", name, i, common.Bytes2Hex(local.Sum(nil)), common.Bytes2Hex(lib.Sum(nil)))
+			}
+
+			// Sum must not change the state, so writing can be continued
+			local.Write(vector.data)
+			lib.Write(vector.data)
+
+			if bytes.Compare(local.Sum(nil), lib.Sum(nil)) != 0 {
+				t.Errorf("hmac.New: wrong result after Sum for %s `%d`", name, i)
+			}
+
+			local.Reset()
+			local.Write([]byte(strings.Repeat("message", 100)))
+			lib.Reset()
+			lib.Write([]byte(strings.Repeat("message", 100)))
+
+			if bytes.Compare(local.Sum(nil), lib.Sum(nil)) != 0 {
+				t.Errorf("hmac.New: wrong result after Reset for %s `%d`", name, i)
+			}
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	mac := Compute(sha256.New, []byte("key"), []byte("message"))
+
+	if !Equal(mac, Compute(sha256.New, []byte("key"), []byte("message"))) {
+		t.Errorf("hmac.Equal: same MACs are not equal")
+	}
+
+	if Equal(mac, Compute(sha256.New, []byte("key"), []byte("massage"))) {
+		t.Errorf("hmac.Equal: different MACs are equal")
+	}
+
+	if Equal(mac, mac[:len(mac)-1]) {
+		t.Errorf("hmac.Equal: MACs of different length are equal")
+	}
+}
@@ -0,0 +1,46 @@
+package pbkdf2
+
+import (
+	"encoding/binary"
+	"hash"
+
+	"github.com/mhrynenko/cryptography_course/hmac"
+)
+
+// Key PBKDF2 from RFC 8018 with HMAC over h as pseudorandom function:
+// T_i = U_1 ^ U_2 ^ ... ^ U_c, U_1 = HMAC(P, S || INT(i)), U_j = HMAC(P, U_{j-1}), DK = T_1 || T_2 || ...
+func Key(h func() hash.Hash, password, salt []byte, iterations, keyLength int) []byte {
+	prf := hmac.New(h, password)
+	size := prf.Size()
+
+	result := make([]byte, 0, keyLength+size)
+	u := make([]byte, 0, size)
+	t := make([]byte, size)
+
+	var counter [4]byte
+	for i := uint32(1); len(result) < keyLength; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+
+		//U_1 = HMAC(P, S || INT(i))
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(counter[:])
+		u = prf.Sum(u[:0])
+		copy(t, u)
+
+		for j := 1; j < iterations; j++ {
+			//U_j = HMAC(P, U_{j-1})
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+
+			for k := range t {
+				t[k] ^= u[k]
+			}
+		}
+
+		result = append(result, t...)
+	}
+
+	return result[:keyLength]
+}
@@ -0,0 +1,57 @@
+package pbkdf2
+
+import (
+	"crypto/sha1"
+	"hash"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mhrynenko/cryptography_course/sha256"
+)
+
+type Vector struct {
+	h          func() hash.Hash
+	password   string
+	salt       string
+	iterations int
+	key        string
+}
+
+// vectors from RFC 6070 (PBKDF2-HMAC-SHA1) and RFC 7914 (PBKDF2-HMAC-SHA256)
+var vectors = []Vector{
+	{h: sha1.New, password: "password", salt: "salt", iterations: 1, key: "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+	{h: sha1.New, password: "password", salt: "salt", iterations: 2, key: "ea6c014dc72d6f8ccd1ed92ace1d41f0d8de8957"},
+	{h: sha1.New, password: "password", salt: "salt", iterations: 4096, key: "4b007901b765489abead49d926f721d065a429c1"},
+	{
+		h:          sha1.New,
+		password:   "passwordPASSWORDpassword",
+		salt:       "saltSALTsaltSALTsaltSALTsaltSALTsalt",
+		iterations: 4096,
+		key:        "3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038",
+	},
+	{h: sha1.New, password: "pass\x00word", salt: "sa\x00lt", iterations: 4096, key: "56fa6aa75548099dcc37d7f03425e0c3"},
+	{
+		h:          sha256.New,
+		password:   "passwd",
+		salt:       "salt",
+		iterations: 1,
+		key:        "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+	},
+	{
+		h:          sha256.New,
+		password:   "Password",
+		salt:       "NaCl",
+		iterations: 80000,
+		key:        "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	for i, vector := range vectors {
+		key := Key(vector.h, []byte(vector.password), []byte(vector.salt), vector.iterations, len(vector.key)/2)
+
+		if common.Bytes2Hex(key) != vector.key {
+			t.Errorf("pbkdf2.Key: wrong key for %d vector", i)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package ripemd160
+
+import (
+	"encoding/binary"
+	"hash"
+)
+
+const (
+	Size      = 20
+	BlockSize = 64
+)
+
+// digest is a streaming RIPEMD160 state, it buffers incomplete block until enough data is written
+type digest struct {
+	h      [5]uint32
+	block  [BlockSize]byte
+	filled int
+	length uint64
+}
+
+// New returns hash.Hash that computes RIPEMD160 checksum
+func New() hash.Hash {
+	d := new(digest)
+	d.Reset()
+	return d
+}
+
+func (d *digest) Reset() {
+	d.h = initialH
+	d.filled = 0
+	d.length = 0
+}
+
+func (d *digest) Size() int {
+	return Size
+}
+
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	written := len(p)
+	d.length += uint64(written)
+
+	if d.filled > 0 {
+		n := copy(d.block[d.filled:], p)
+		d.filled += n
+		p = p[n:]
+
+		if d.filled < BlockSize {
+			return written, nil
+		}
+
+		compression(&d.h, d.block[:])
+		d.filled = 0
+	}
+
+	for len(p) >= BlockSize {
+		compression(&d.h, p[:BlockSize])
+		p = p[BlockSize:]
+	}
+
+	d.filled = copy(d.block[:], p)
+
+	return written, nil
+}
+
+// Sum appends current hash to b, the state of d is not changed, so writing can be continued
+func (d *digest) Sum(b []byte) []byte {
+	// work with copy to keep d untouched
+	tmp := *d
+	result := tmp.checkSum()
+
+	return append(b, result[:]...)
+}
+
+// checkSum the same padding as in MD4 family: 1 bit, zeros and 64-bit message length, but little endian
+func (d *digest) checkSum() [Size]byte {
+	messageLength := d.length
+
+	var padding [BlockSize + 8]byte
+	padding[0] = 0x80
+
+	zerosAmount := (64 + 55 - messageLength%64) % 64
+	binary.LittleEndian.PutUint64(padding[1+zerosAmount:], messageLength<<3)
+
+	d.Write(padding[:1+zerosAmount+8])
+
+	if d.filled != 0 {
+		panic("padded message length is invalid")
+	}
+
+	return getBytesResult(d.h)
+}
@@ -0,0 +1,61 @@
+package ripemd160
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Vector struct {
+	input  string
+	result string
+}
+
+// vectors from RIPEMD-160 specification page
+var vectors = []Vector{
+	{input: "", result: "9c1185a5c5e9fc54612808977ee8f548b2258d31"},
+	{input: "a", result: "0bdc9d2d256b3ee9daae347be6f4dc835a467ffe"},
+	{input: "abc", result: "8eb208f7e05d987a9b044a8e98c6b087f15a0bfc"},
+	{input: "message digest", result: "5d0689ef49d2fae572b881b123a85ffa21595f36"},
+	{input: "abcdefghijklmnopqrstuvwxyz", result: "f71c27109c692c1b56bbdceb5b9d2865b3708dbc"},
+	{input: "abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq", result: "12a053384a9c0c88e405a06c27dcf49ada62eb2b"},
+	{input: "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789", result: "b0e20b6e3116640286ed3a87a5713079b21f5189"},
+	{input: strings.Repeat("1234567890", 8), result: "9b752e45573d4b39f4dbd3323cab82bf63326bfb"},
+	{input: strings.Repeat("a", 1000000), result: "52783243c1697bdbe16d37f97f68f08325dc1528"},
+}
+
+func TestVectors(t *testing.T) {
+	for i, vector := range vectors {
+		result := Compute([]byte(vector.input))
+		if common.Bytes2Hex(result[:]) != vector.result {
+			t.Errorf("ripemd160.Compute: wrong hash for %d vector", i)
+		}
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	for i, vector := range vectors {
+		h := New()
+
+		// write by pieces of different length to check block buffering
+		input := []byte(vector.input)
+		for step := 1; len(input) > 0; step = step%97 + 1 {
+			if step > len(input) {
+				step = len(input)
+			}
+
+			h.Write(input[:step])
+			input = input[step:]
+		}
+
+		if common.Bytes2Hex(h.Sum(nil)) != vector.result {
+			t.Errorf("ripemd160.New: wrong hash for %d vector", i)
+		}
+
+		// Sum does not change the state
+		if common.Bytes2Hex(h.Sum(nil)) != vector.result {
+			t.Errorf("ripemd160.Sum: state is changed for %d vector", i)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package schnorr
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/mhrynenko/cryptography_course/ecdsa"
+	"github.com/mhrynenko/cryptography_course/secp256k1"
+	"github.com/mhrynenko/cryptography_course/sha256"
+	"github.com/pkg/errors"
+)
+
+const (
+	PublicKeySize = 32
+	SignatureSize = 64
+	AuxRandSize   = 32
+
+	tagAux       = "BIP0340/aux"
+	tagNonce     = "BIP0340/nonce"
+	tagChallenge = "BIP0340/challenge"
+)
+
+var (
+	ErrInvalidPrivateKey          = errors.New("private key is out of range")
+	ErrInvalidPublicKey           = errors.New("public key is not X coordinate of curve point")
+	ErrInvalidPublicKeyEncoding   = errors.New("public key encoding is invalid")
+	ErrInvalidSignatureEncoding   = errors.New("signature encoding is invalid")
+	ErrSignatureValueOutOfRange   = errors.New("signature value is out of range")
+	ErrInvalidAuxRand             = errors.New("auxiliary random data must be 32 bytes")
+	ErrZeroNonce                  = errors.New("nonce is zero")
+	ErrFailedToVerifyOwnSignature = errors.New("created signature does not pass verification")
+)
+
+var curve = secp256k1.S256()
+
+// PublicKey x-only public key, Y coordinate is always even, so it is not stored
+type PublicKey struct {
+	X *big.Int
+}
+
+// PrivateKey D is always such that D x G has even Y, so it can be used for signing as is
+type PrivateKey struct {
+	PK PublicKey
+	D  *big.Int
+}
+
+// Signature R is X coordinate of nonce point (with even Y), S = k + e*d mod n
+type Signature struct {
+	R *big.Int
+	S *big.Int
+}
+
+func GeneratePrivateKey() (*PrivateKey, error) {
+	d, err := rand.Int(rand.Reader, new(big.Int).Sub(curve.Params().N, big.NewInt(1)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate random big int")
+	}
+
+	// d ∈ [1, n - 1]
+	return NewPrivateKey(d.Add(d, big.NewInt(1)))
+}
+
+// NewPrivateKey d x G = P (in constant time), public key is X coordinate of P,
+// d is replaced with n - d when P has odd Y
+func NewPrivateKey(d *big.Int) (*PrivateKey, error) {
+	params := curve.Params()
+
+	if d == nil || d.Sign() <= 0 || d.Cmp(params.N) >= 0 {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	x, y := ecdsa.ScalarMult(curve, params.Gx, params.Gy, d)
+
+	D := new(big.Int).Set(d)
+	if y.Bit(0) == 1 {
+		D.Sub(params.N, D)
+	}
+
+	return &PrivateKey{
+		PK: PublicKey{X: x},
+		D:  D,
+	}, nil
+}
+
+// Bytes 32-byte big endian X coordinate
+func (pk PublicKey) Bytes() []byte {
+	return intToBytes(pk.X)
+}
+
+// ParsePublicKey accepts only X coordinate of curve point
+func ParsePublicKey(data []byte) (*PublicKey, error) {
+	if len(data) != PublicKeySize {
+		return nil, ErrInvalidPublicKeyEncoding
+	}
+
+	x := new(big.Int).SetBytes(data)
+	if _, err := liftX(x); err != nil {
+		return nil, err
+	}
+
+	return &PublicKey{X: x}, nil
+}
+
+// Bytes R || S, 64 bytes
+func (sig *Signature) Bytes() []byte {
+	return append(intToBytes(sig.R), intToBytes(sig.S)...)
+}
+
+// ParseSignature checks only encoding and ranges r < p, s < n, the rest is checked by Verify
+func ParseSignature(data []byte) (*Signature, error) {
+	if len(data) != SignatureSize {
+		return nil, ErrInvalidSignatureEncoding
+	}
+
+	sig := &Signature{
+		R: new(big.Int).SetBytes(data[:32]),
+		S: new(big.Int).SetBytes(data[32:]),
+	}
+
+	if sig.R.Cmp(curve.Params().P) >= 0 || sig.S.Cmp(curve.Params().N) >= 0 {
+		return nil, ErrSignatureValueOutOfRange
+	}
+
+	return sig, nil
+}
+
+// Sign signs message of any length with fresh auxiliary random data
+func Sign(key *PrivateKey, msg []byte) (*Signature, error) {
+	auxRand := make([]byte, AuxRandSize)
+	if _, err := rand.Read(auxRand); err != nil {
+		return nil, errors.Wrap(err, "failed to generate auxiliary random data")
+	}
+
+	return SignWithAuxRand(key, msg, auxRand)
+}
+
+// SignWithAuxRand BIP-340 signing, auxRand only protects against side channels, signature is valid with any value
+func SignWithAuxRand(key *PrivateKey, msg, auxRand []byte) (*Signature, error) {
+	if len(auxRand) != AuxRandSize {
+		return nil, ErrInvalidAuxRand
+	}
+
+	params := curve.Params()
+	N := params.N
+
+	if key == nil || key.D == nil || key.D.Sign() <= 0 || key.D.Cmp(N) >= 0 || key.PK.X == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	// P = d x G with even Y is already computed by NewPrivateKey, mismatched key fails self verification
+	d := key.D
+	pkBytes := key.PK.Bytes()
+
+	//t = d xor hash_aux(a)
+	t := intToBytes(d)
+	for i, b := range TaggedHash(tagAux, auxRand) {
+		t[i] ^= b
+	}
+
+	//k' = hash_nonce(t || P || m) mod n
+	k := new(big.Int).SetBytes(TaggedHash(tagNonce, t, pkBytes, msg))
+	k.Mod(k, N)
+	if k.Sign() == 0 {
+		return nil, ErrZeroNonce
+	}
+
+	//R = k' x G (in constant time), k = k' if R has even Y, otherwise n - k'
+	rx, ry := ecdsa.ScalarMult(curve, params.Gx, params.Gy, k)
+	if ry.Bit(0) == 1 {
+		k.Sub(N, k)
+	}
+
+	//e = hash_challenge(R || P || m) mod n
+	e := challenge(rx, pkBytes, msg)
+
+	//s = k + e*d mod n
+	s := e.Mul(e, d)
+	s.Add(s, k)
+	s.Mod(s, N)
+
+	sig := &Signature{R: rx, S: s}
+
+	// protects against faults during computation, as BIP-340 recommends
+	isVerified, err := Verify(key.PK, msg, sig)
+	if err != nil || !isVerified {
+		return nil, ErrFailedToVerifyOwnSignature
+	}
+
+	return sig, nil
+}
+
+// Verify BIP-340 verification, error is returned for malformed public key or signature
+func Verify(pk PublicKey, msg []byte, sig *Signature) (bool, error) {
+	if pk.X == nil {
+		return false, ErrInvalidPublicKey
+	}
+
+	//P = lift_x(pk)
+	py, err := liftX(pk.X)
+	if err != nil {
+		return false, err
+	}
+
+	if sig == nil || sig.R == nil || sig.S == nil {
+		return false, ErrInvalidSignatureEncoding
+	}
+
+	//r < p, s < n
+	if sig.R.Sign() < 0 || sig.R.Cmp(curve.Params().P) >= 0 || sig.S.Sign() < 0 || sig.S.Cmp(curve.Params().N) >= 0 {
+		return false, ErrSignatureValueOutOfRange
+	}
+
+	N := curve.Params().N
+
+	//e = hash_challenge(r || P || m) mod n
+	e := challenge(sig.R, intToBytes(pk.X), msg)
+
+	//R = s x G - e x P, all values are public, so variable time multiplication is used
+	x1, y1 := curve.ScalarBaseMult(sig.S.Bytes())
+	x2, y2 := curve.ScalarMult(pk.X, py, e.Sub(N, e).Bytes())
+	rx, ry := curve.Add(x1, y1, x2, y2)
+
+	// R is the point at infinity
+	if len(rx.Bits()) == 0 && len(ry.Bits()) == 0 {
+		return false, nil
+	}
+
+	return ry.Bit(0) == 0 && rx.Cmp(sig.R) == 0, nil
+}
+
+// TaggedHash SHA256(SHA256(tag) || SHA256(tag) || data), different tags give independent hash functions
+func TaggedHash(tag string, data ...[]byte) []byte {
+	tagHash := sha256.Compute([]byte(tag))
+
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+
+	for _, d := range data {
+		h.Write(d)
+	}
+
+	return h.Sum(nil)
+}
+
+func challenge(rx *big.Int, pkBytes, msg []byte) *big.Int {
+	e := new(big.Int).SetBytes(TaggedHash(tagChallenge, intToBytes(rx), pkBytes, msg))
+
+	return e.Mod(e, curve.Params().N)
+}
+
+// liftX returns even Y for X: y = c^((p + 1) / 4) mod p, where c = x^3 + 7 (p = 3 mod 4)
+func liftX(x *big.Int) (*big.Int, error) {
+	P := curve.Params().P
+
+	if x.Sign() < 0 || x.Cmp(P) >= 0 {
+		return nil, ErrInvalidPublicKey
+	}
+
+	c := new(big.Int).Exp(x, big.NewInt(3), P)
+	c.Add(c, curve.Params().B)
+	c.Mod(c, P)
+
+	exp := new(big.Int).Add(P, big.NewInt(1))
+	exp.Rsh(exp, 2)
+
+	y := new(big.Int).Exp(c, exp, P)
+
+	// c is not a quadratic residue
+	if new(big.Int).Exp(y, big.NewInt(2), P).Cmp(c) != 0 {
+		return nil, ErrInvalidPublicKey
+	}
+
+	if y.Bit(0) == 1 {
+		y.Sub(P, y)
+	}
+
+	return y, nil
+}
+
+func intToBytes(x *big.Int) []byte {
+	return x.FillBytes(make([]byte, 32))
+}
@@ -0,0 +1,206 @@
+package schnorr
+
+import (
+	stdsha256 "crypto/sha256"
+	"encoding/csv"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestBIP340Vectors runs official test-vectors.csv from BIP-340
+func TestBIP340Vectors(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "test-vectors.csv"))
+	if err != nil {
+		t.Fatalf("os.Open: unexcpected error `%s`", err.Error())
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("csv.ReadAll: unexcpected error `%s`", err.Error())
+	}
+
+	for _, record := range records[1:] {
+		index, secretKey, publicKey, auxRand, message, signature := record[0], record[1], record[2], record[3], record[4], record[5]
+		expected := record[6] == "TRUE"
+		msg := common.FromHex(message)
+
+		if secretKey != "" {
+			key, err := NewPrivateKey(new(big.Int).SetBytes(common.FromHex(secretKey)))
+			if err != nil {
+				t.Fatalf("schnorr.NewPrivateKey: unexcpected error `%s` for %s vector", err.Error(), index)
+			}
+
+			if !strings.EqualFold(common.Bytes2Hex(key.PK.Bytes()), publicKey) {
+				t.Errorf("schnorr.NewPrivateKey: wrong public key for %s vector", index)
+			}
+
+			sig, err := SignWithAuxRand(key, msg, common.FromHex(auxRand))
+			if err != nil {
+				t.Fatalf("schnorr.SignWithAuxRand: unexcpected error `%s` for %s vector", err.Error(), index)
+			}
+
+			if !strings.EqualFold(common.Bytes2Hex(sig.Bytes()), signature) {
+				t.Errorf("schnorr.SignWithAuxRand: wrong signature for %s vector", index)
+			}
+		}
+
+		if isVerified := verifyEncoded(common.FromHex(publicKey), msg, common.FromHex(signature)); isVerified != expected {
+			t.Errorf("schnorr.Verify: expected %v, got %v for %s vector (%s)", expected, isVerified, index, record[7])
+		}
+	}
+}
+
+// verifyEncoded malformed public key or signature means failed verification
+func verifyEncoded(publicKey, msg, signature []byte) bool {
+	pk, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return false
+	}
+
+	sig, err := ParseSignature(signature)
+	if err != nil {
+		return false
+	}
+
+	isVerified, err := Verify(*pk, msg, sig)
+
+	return err == nil && isVerified
+}
+
+func TestSignVerify(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		key, err := GeneratePrivateKey()
+		if err != nil {
+			t.Fatalf("schnorr.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+		}
+
+		msg := []byte(strings.Repeat("Hello world!", i))
+
+		sig, err := Sign(key, msg)
+		if err != nil {
+			t.Fatalf("schnorr.Sign: unexcpected error `%s`", err.Error())
+		}
+
+		isVerified, err := Verify(key.PK, msg, sig)
+		if err != nil || !isVerified {
+			t.Errorf("schnorr.Verify: valid signature is rejected for %d vector, err = %v", i, err)
+		}
+
+		isVerified, err = Verify(key.PK, append(msg, '!'), sig)
+		if err != nil || isVerified {
+			t.Errorf("schnorr.Verify: signature of another message is accepted for %d vector, err = %v", i, err)
+		}
+
+		parsed, err := ParseSignature(sig.Bytes())
+		if err != nil || parsed.R.Cmp(sig.R) != 0 || parsed.S.Cmp(sig.S) != 0 {
+			t.Errorf("schnorr.ParseSignature: signature is not restored for %d vector", i)
+		}
+
+		pk, err := ParsePublicKey(key.PK.Bytes())
+		if err != nil || pk.X.Cmp(key.PK.X) != 0 {
+			t.Errorf("schnorr.ParsePublicKey: public key is not restored for %d vector", i)
+		}
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	key, err := GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("schnorr.GeneratePrivateKey: unexcpected error `%s`", err.Error())
+	}
+
+	if _, err := NewPrivateKey(big.NewInt(0)); err != ErrInvalidPrivateKey {
+		t.Errorf("schnorr.NewPrivateKey: expected error for zero key")
+	}
+
+	if _, err := NewPrivateKey(curve.Params().N); err != ErrInvalidPrivateKey {
+		t.Errorf("schnorr.NewPrivateKey: expected error for key equal to N")
+	}
+
+	if _, err := SignWithAuxRand(key, nil, make([]byte, 31)); err != ErrInvalidAuxRand {
+		t.Errorf("schnorr.SignWithAuxRand: expected error for short auxiliary data")
+	}
+
+	if _, err := ParsePublicKey(make([]byte, 33)); err != ErrInvalidPublicKeyEncoding {
+		t.Errorf("schnorr.ParsePublicKey: expected error for 33-byte key")
+	}
+
+	if _, err := ParseSignature(make([]byte, 65)); err != ErrInvalidSignatureEncoding {
+		t.Errorf("schnorr.ParseSignature: expected error for 65-byte signature")
+	}
+
+	if _, err := Verify(key.PK, nil, nil); err != ErrInvalidSignatureEncoding {
+		t.Errorf("schnorr.Verify: expected error for nil signature")
+	}
+
+	if _, err := Verify(PublicKey{}, nil, &Signature{R: big.NewInt(1), S: big.NewInt(1)}); err != ErrInvalidPublicKey {
+		t.Errorf("schnorr.Verify: expected error for nil public key")
+	}
+}
+
+// TestKeyNormalization key with odd Y is stored negated, signing uses stored public key, so mismatched key is rejected
+func TestKeyNormalization(t *testing.T) {
+	N := curve.Params().N
+
+	for i := 1; i <= 8; i++ {
+		d := big.NewInt(int64(i))
+
+		key, err := NewPrivateKey(d)
+		if err != nil {
+			t.Fatalf("schnorr.NewPrivateKey: unexcpected error `%s` for %d vector", err.Error(), i)
+		}
+
+		x, y := curve.ScalarBaseMult(d.Bytes())
+		if key.PK.X.Cmp(x) != 0 {
+			t.Errorf("schnorr.NewPrivateKey: wrong public key for %d vector", i)
+		}
+
+		expected := new(big.Int).Set(d)
+		if y.Bit(0) == 1 {
+			expected.Sub(N, expected)
+		}
+
+		if key.D.Cmp(expected) != 0 {
+			t.Errorf("schnorr.NewPrivateKey: private key is not normalized for %d vector", i)
+		}
+
+		negated, err := NewPrivateKey(new(big.Int).Sub(N, d))
+		if err != nil || negated.D.Cmp(key.D) != 0 || negated.PK.X.Cmp(key.PK.X) != 0 {
+			t.Errorf("schnorr.NewPrivateKey: d and n - d give different keys for %d vector", i)
+		}
+	}
+
+	key, err := NewPrivateKey(big.NewInt(3))
+	if err != nil {
+		t.Fatalf("schnorr.NewPrivateKey: unexcpected error `%s`", err.Error())
+	}
+
+	other, err := NewPrivateKey(big.NewInt(5))
+	if err != nil {
+		t.Fatalf("schnorr.NewPrivateKey: unexcpected error `%s`", err.Error())
+	}
+
+	mismatched := &PrivateKey{PK: other.PK, D: key.D}
+	if _, err := SignWithAuxRand(mismatched, []byte("msg"), make([]byte, AuxRandSize)); err != ErrFailedToVerifyOwnSignature {
+		t.Errorf("schnorr.SignWithAuxRand: expected error for mismatched public key")
+	}
+
+	if _, err := SignWithAuxRand(&PrivateKey{D: key.D}, []byte("msg"), make([]byte, AuxRandSize)); err != ErrInvalidPrivateKey {
+		t.Errorf("schnorr.SignWithAuxRand: expected error for missing public key")
+	}
+}
+
+func TestTaggedHash(t *testing.T) {
+	tag := stdsha256.Sum256([]byte(tagChallenge))
+	expected := stdsha256.Sum256(append(append(tag[:], tag[:]...), []byte("Hello world!")...))
+
+	if common.Bytes2Hex(TaggedHash(tagChallenge, []byte("Hello "), []byte("world!"))) != common.Bytes2Hex(expected[:]) {
+		t.Errorf("schnorr.TaggedHash: wrong hash")
+	}
+}
@@ -0,0 +1,251 @@
+package secp256k1
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// Curve secp256k1 y^2 = x^3 + 7, unlike NIST curves a = 0, so generic elliptic.CurveParams arithmetic can not be used
+type Curve struct {
+	params *elliptic.CurveParams
+}
+
+var secp256k1 = &Curve{
+	params: &elliptic.CurveParams{
+		P:       fromHex("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F"),
+		N:       fromHex("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141"),
+		B:       big.NewInt(7),
+		Gx:      fromHex("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798"),
+		Gy:      fromHex("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8"),
+		BitSize: 256,
+		Name:    "secp256k1",
+	},
+}
+
+// S256 returns secp256k1 curve (the one used in Bitcoin and Ethereum)
+func S256() elliptic.Curve {
+	return secp256k1
+}
+
+func fromHex(s string) *big.Int {
+	result, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex: " + s)
+	}
+
+	return result
+}
+
+func (curve *Curve) Params() *elliptic.CurveParams {
+	return curve.params
+}
+
+// IsOnCurve y^2 = x^3 + 7 mod p
+func (curve *Curve) IsOnCurve(x, y *big.Int) bool {
+	P := curve.params.P
+
+	if x.Sign() < 0 || x.Cmp(P) >= 0 || y.Sign() < 0 || y.Cmp(P) >= 0 {
+		return false
+	}
+
+	left := new(big.Int).Mul(y, y)
+	left.Mod(left, P)
+
+	right := new(big.Int).Mul(x, x)
+	right.Mul(right, x)
+	right.Add(right, curve.params.B)
+	right.Mod(right, P)
+
+	return left.Cmp(right) == 0
+}
+
+func (curve *Curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	return curve.toAffine(curve.addJacobian(curve.toJacobian(x1, y1), curve.toJacobian(x2, y2)))
+}
+
+func (curve *Curve) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	return curve.toAffine(curve.doubleJacobian(curve.toJacobian(x1, y1)))
+}
+
+// ScalarMult k*(x, y) with double-and-add, k is big endian
+func (curve *Curve) ScalarMult(x, y *big.Int, k []byte) (*big.Int, *big.Int) {
+	point := curve.toJacobian(x, y)
+	result := jacobianPoint{x: new(big.Int), y: new(big.Int), z: new(big.Int)}
+
+	for _, b := range k {
+		for bit := 7; bit >= 0; bit-- {
+			result = curve.doubleJacobian(result)
+
+			if (b>>bit)&1 == 1 {
+				result = curve.addJacobian(result, point)
+			}
+		}
+	}
+
+	return curve.toAffine(result)
+}
+
+func (curve *Curve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return curve.ScalarMult(curve.params.Gx, curve.params.Gy, k)
+}
+
+// jacobianPoint (X, Y, Z) represents affine point (X/Z^2, Y/Z^3), so addition does not need modular inversion,
+// Z = 0 is the point at infinity
+type jacobianPoint struct {
+	x, y, z *big.Int
+}
+
+// toJacobian (0, 0) is the point at infinity in elliptic.Curve API
+func (curve *Curve) toJacobian(x, y *big.Int) jacobianPoint {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return jacobianPoint{x: new(big.Int), y: new(big.Int), z: new(big.Int)}
+	}
+
+	return jacobianPoint{x: new(big.Int).Set(x), y: new(big.Int).Set(y), z: big.NewInt(1)}
+}
+
+func (curve *Curve) toAffine(point jacobianPoint) (*big.Int, *big.Int) {
+	if point.z.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+
+	P := curve.params.P
+
+	zInverse := new(big.Int).ModInverse(point.z, P)
+	zInverse2 := new(big.Int).Mul(zInverse, zInverse)
+
+	x := new(big.Int).Mul(point.x, zInverse2)
+	x.Mod(x, P)
+
+	y := new(big.Int).Mul(point.y, zInverse2.Mul(zInverse2, zInverse))
+	y.Mod(y, P)
+
+	return x, y
+}
+
+// doubleJacobian "dbl-2009-l" formulas for a = 0
+func (curve *Curve) doubleJacobian(point jacobianPoint) jacobianPoint {
+	P := curve.params.P
+
+	if point.z.Sign() == 0 || point.y.Sign() == 0 {
+		return jacobianPoint{x: new(big.Int), y: new(big.Int), z: new(big.Int)}
+	}
+
+	//A = X1^2, B = Y1^2, C = B^2
+	A := new(big.Int).Mul(point.x, point.x)
+	A.Mod(A, P)
+	B := new(big.Int).Mul(point.y, point.y)
+	B.Mod(B, P)
+	C := new(big.Int).Mul(B, B)
+	C.Mod(C, P)
+
+	//D = 2*((X1+B)^2-A-C)
+	D := new(big.Int).Add(point.x, B)
+	D.Mul(D, D)
+	D.Sub(D, A)
+	D.Sub(D, C)
+	D.Lsh(D, 1)
+	D.Mod(D, P)
+
+	//E = 3*A, F = E^2
+	E := new(big.Int).Mul(big.NewInt(3), A)
+	F := new(big.Int).Mul(E, E)
+
+	//X3 = F-2*D
+	x3 := new(big.Int).Sub(F, new(big.Int).Lsh(D, 1))
+	x3.Mod(x3, P)
+
+	//Y3 = E*(D-X3)-8*C
+	y3 := new(big.Int).Sub(D, x3)
+	y3.Mul(y3, E)
+	y3.Sub(y3, C.Lsh(C, 3))
+	y3.Mod(y3, P)
+
+	//Z3 = 2*Y1*Z1
+	z3 := new(big.Int).Mul(point.y, point.z)
+	z3.Lsh(z3, 1)
+	z3.Mod(z3, P)
+
+	return jacobianPoint{x: x3, y: y3, z: z3}
+}
+
+// addJacobian "add-2007-bl" formulas
+func (curve *Curve) addJacobian(p1, p2 jacobianPoint) jacobianPoint {
+	P := curve.params.P
+
+	if p1.z.Sign() == 0 {
+		return p2
+	}
+
+	if p2.z.Sign() == 0 {
+		return p1
+	}
+
+	//Z1Z1 = Z1^2, Z2Z2 = Z2^2
+	z1z1 := new(big.Int).Mul(p1.z, p1.z)
+	z1z1.Mod(z1z1, P)
+	z2z2 := new(big.Int).Mul(p2.z, p2.z)
+	z2z2.Mod(z2z2, P)
+
+	//U1 = X1*Z2Z2, U2 = X2*Z1Z1
+	u1 := new(big.Int).Mul(p1.x, z2z2)
+	u1.Mod(u1, P)
+	u2 := new(big.Int).Mul(p2.x, z1z1)
+	u2.Mod(u2, P)
+
+	//S1 = Y1*Z2*Z2Z2, S2 = Y2*Z1*Z1Z1
+	s1 := new(big.Int).Mul(p1.y, p2.z)
+	s1.Mul(s1, z2z2)
+	s1.Mod(s1, P)
+	s2 := new(big.Int).Mul(p2.y, p1.z)
+	s2.Mul(s2, z1z1)
+	s2.Mod(s2, P)
+
+	//H = U2-U1, r = 2*(S2-S1)
+	h := new(big.Int).Sub(u2, u1)
+	h.Mod(h, P)
+	r := new(big.Int).Sub(s2, s1)
+	r.Mod(r, P)
+
+	if h.Sign() == 0 {
+		// the same point has to be doubled, opposite points give infinity
+		if r.Sign() == 0 {
+			return curve.doubleJacobian(p1)
+		}
+
+		return jacobianPoint{x: new(big.Int), y: new(big.Int), z: new(big.Int)}
+	}
+
+	r.Lsh(r, 1)
+
+	//I = (2*H)^2, J = H*I, V = U1*I
+	i := new(big.Int).Lsh(h, 1)
+	i.Mul(i, i)
+	i.Mod(i, P)
+	j := new(big.Int).Mul(h, i)
+	j.Mod(j, P)
+	v := new(big.Int).Mul(u1, i)
+	v.Mod(v, P)
+
+	//X3 = r^2-J-2*V
+	x3 := new(big.Int).Mul(r, r)
+	x3.Sub(x3, j)
+	x3.Sub(x3, new(big.Int).Lsh(v, 1))
+	x3.Mod(x3, P)
+
+	//Y3 = r*(V-X3)-2*S1*J
+	y3 := new(big.Int).Sub(v, x3)
+	y3.Mul(y3, r)
+	y3.Sub(y3, s1.Mul(s1, j).Lsh(s1, 1))
+	y3.Mod(y3, P)
+
+	//Z3 = ((Z1+Z2)^2-Z1Z1-Z2Z2)*H
+	z3 := new(big.Int).Add(p1.z, p2.z)
+	z3.Mul(z3, z3)
+	z3.Sub(z3, z1z1)
+	z3.Sub(z3, z2z2)
+	z3.Mul(z3, h)
+	z3.Mod(z3, P)
+
+	return jacobianPoint{x: x3, y: y3, z: z3}
+}
@@ -0,0 +1,91 @@
+package secp256k1
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestParams(t *testing.T) {
+	local := S256().Params()
+	lib := crypto.S256().Params()
+
+	if local.P.Cmp(lib.P) != 0 || local.N.Cmp(lib.N) != 0 || local.B.Cmp(lib.B) != 0 ||
+		local.Gx.Cmp(lib.Gx) != 0 || local.Gy.Cmp(lib.Gy) != 0 || local.BitSize != lib.BitSize {
+		t.Errorf("secp256k1.S256: params are not the same as in go-ethereum")
+	}
+
+	if !S256().IsOnCurve(local.Gx, local.Gy) {
+		t.Errorf("secp256k1.IsOnCurve: generator is not on curve")
+	}
+
+	if S256().IsOnCurve(local.Gx, new(big.Int).Add(local.Gy, big.NewInt(1))) {
+		t.Errorf("secp256k1.IsOnCurve: wrong point is on curve")
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	curve := S256()
+	lib := crypto.S256()
+	N := curve.Params().N
+
+	for i := 0; i < 32; i++ {
+		k1, _ := rand.Int(rand.Reader, N)
+		k2, _ := rand.Int(rand.Reader, N)
+
+		x1, y1 := curve.ScalarBaseMult(k1.Bytes())
+		libX1, libY1 := lib.ScalarBaseMult(k1.Bytes())
+		if x1.Cmp(libX1) != 0 || y1.Cmp(libY1) != 0 {
+			t.Errorf("secp256k1.ScalarBaseMult: wrong result for %d", i)
+		}
+
+		if !curve.IsOnCurve(x1, y1) {
+			t.Errorf("secp256k1.ScalarBaseMult: result is not on curve for %d", i)
+		}
+
+		x2, y2 := curve.ScalarMult(x1, y1, k2.Bytes())
+		libX2, libY2 := lib.ScalarMult(libX1, libY1, k2.Bytes())
+		if x2.Cmp(libX2) != 0 || y2.Cmp(libY2) != 0 {
+			t.Errorf("secp256k1.ScalarMult: wrong result for %d", i)
+		}
+
+		x3, y3 := curve.Add(x1, y1, x2, y2)
+		libX3, libY3 := lib.Add(libX1, libY1, libX2, libY2)
+		if x3.Cmp(libX3) != 0 || y3.Cmp(libY3) != 0 {
+			t.Errorf("secp256k1.Add: wrong result for %d", i)
+		}
+
+		x4, y4 := curve.Double(x1, y1)
+		libX4, libY4 := lib.Double(libX1, libY1)
+		if x4.Cmp(libX4) != 0 || y4.Cmp(libY4) != 0 {
+			t.Errorf("secp256k1.Double: wrong result for %d", i)
+		}
+
+		// P + P must be the same as 2P
+		x5, y5 := curve.Add(x1, y1, x1, y1)
+		if x5.Cmp(x4) != 0 || y5.Cmp(y4) != 0 {
+			t.Errorf("secp256k1.Add: adding point to itself differs from doubling for %d", i)
+		}
+
+		// P + (-P) is the point at infinity
+		x6, y6 := curve.Add(x1, y1, x1, new(big.Int).Sub(curve.Params().P, y1))
+		if x6.Sign() != 0 || y6.Sign() != 0 {
+			t.Errorf("secp256k1.Add: P + (-P) is not infinity for %d", i)
+		}
+	}
+
+	// n*G is the point at infinity
+	x, y := curve.ScalarBaseMult(N.Bytes())
+	if x.Sign() != 0 || y.Sign() != 0 {
+		t.Errorf("secp256k1.ScalarBaseMult: n*G is not infinity")
+	}
+
+	// infinity is neutral element
+	Gx, Gy := curve.Params().Gx, curve.Params().Gy
+	x, y = curve.Add(Gx, Gy, new(big.Int), new(big.Int))
+	if x.Cmp(Gx) != 0 || y.Cmp(Gy) != 0 {
+		t.Errorf("secp256k1.Add: G + infinity is not G")
+	}
+}
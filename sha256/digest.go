@@ -0,0 +1,115 @@
+package sha256
+
+import (
+	"encoding/binary"
+	"hash"
+)
+
+const (
+	Size      = 32
+	Size224   = 28
+	BlockSize = 64
+)
+
+// digest is a streaming SHA256 state, it buffers incomplete block until enough data is written
+type digest struct {
+	h      [8]uint32
+	block  [BlockSize]byte
+	filled int
+	length uint64
+	is224  bool
+}
+
+// New returns hash.Hash that computes SHA256 checksum
+func New() hash.Hash {
+	d := new(digest)
+	d.Reset()
+	return d
+}
+
+// New224 returns hash.Hash that computes SHA224 checksum
+func New224() hash.Hash {
+	d := &digest{is224: true}
+	d.Reset()
+	return d
+}
+
+func (d *digest) Reset() {
+	d.h = initialH
+	if d.is224 {
+		d.h = initialH224
+	}
+	d.filled = 0
+	d.length = 0
+}
+
+func (d *digest) Size() int {
+	if d.is224 {
+		return Size224
+	}
+
+	return Size
+}
+
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	written := len(p)
+	d.length += uint64(written)
+
+	if d.filled > 0 {
+		n := copy(d.block[d.filled:], p)
+		d.filled += n
+		p = p[n:]
+
+		if d.filled < BlockSize {
+			return written, nil
+		}
+
+		d.processBlock(d.block[:])
+		d.filled = 0
+	}
+
+	for len(p) >= BlockSize {
+		d.processBlock(p[:BlockSize])
+		p = p[BlockSize:]
+	}
+
+	d.filled = copy(d.block[:], p)
+
+	return written, nil
+}
+
+// Sum appends current hash to b, the state of d is not changed, so writing can be continued
+func (d *digest) Sum(b []byte) []byte {
+	// work with copy to keep d untouched
+	tmp := *d
+	result := tmp.checkSum()
+
+	return append(b, result[:d.Size()]...)
+}
+
+func (d *digest) processBlock(block []byte) {
+	schedule := prepareMessageSchedule(block)
+	compression(&d.h, schedule)
+}
+
+func (d *digest) checkSum() [Size]byte {
+	messageLength := d.length
+
+	var padding [BlockSize + 8]byte
+	padding[0] = 0x80
+
+	zerosAmount := (64 + 55 - messageLength%64) % 64
+	binary.BigEndian.PutUint64(padding[1+zerosAmount:], messageLength*8)
+
+	d.Write(padding[:1+zerosAmount+8])
+
+	if d.filled != 0 {
+		panic("padded message length is invalid")
+	}
+
+	return getBytesResult(d.h)
+}
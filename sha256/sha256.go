@@ -2,7 +2,6 @@ package sha256
 
 import (
 	"encoding/binary"
-	"math"
 )
 
 var K = [64]uint32{
@@ -16,51 +15,12 @@ var K = [64]uint32{
 	0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
 }
 
-var H = [8]uint32{}
-
-func initH() {
-	H = [8]uint32{
-		0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
-	}
-}
-
-func paddingMessage(data []byte) []byte {
-	messageLength := len(data)
-
-	var res = append(data, 0x80)
-
-	zerosAmount := (56 - (messageLength + 1)) % 64
-	if zerosAmount < 0 {
-		zerosAmount += 64
-	}
-
-	var zeroes = make([]byte, zerosAmount)
-	res = append(res, zeroes...)
-
-	var length = make([]byte, 8)
-	binary.BigEndian.PutUint64(length, uint64(messageLength*8))
-	res = append(res, length...)
-
-	return res
+var initialH = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
 }
 
-func parsePaddedMessage(message []byte) [][]byte {
-	var messageLength = len(message)
-
-	if messageLength == 0 || messageLength >= math.MaxInt64 || messageLength%64 != 0 {
-		panic("message length is invalid")
-	}
-
-	var blocksAmount = messageLength / 64
-	var blocks = make([][]byte, blocksAmount)
-
-	for i := 0; i < blocksAmount; i++ {
-		block := make([]byte, 64)
-		copy(block, message[i*64:])
-		blocks[i] = block
-	}
-
-	return blocks
+var initialH224 = [8]uint32{
+	0xc1059ed8, 0x367cd507, 0x3070dd17, 0xf70e5939, 0xffc00b31, 0x68581511, 0x64f98fa7, 0xbefa4fa4,
 }
 
 func prepareMessageSchedule(block []byte) [64]uint32 {
@@ -80,7 +40,7 @@ func prepareMessageSchedule(block []byte) [64]uint32 {
 	return schedule
 }
 
-func compression(schedule [64]uint32) {
+func compression(H *[8]uint32, schedule [64]uint32) {
 	a := H[0]
 	b := H[1]
 	c := H[2]
@@ -114,7 +74,7 @@ func compression(schedule [64]uint32) {
 	H[7] = h + H[7]
 }
 
-func getBytesResult() [32]byte {
+func getBytesResult(H [8]uint32) [32]byte {
 	var result [32]byte
 
 	for i := 0; i < 32; i += 4 {
@@ -127,15 +87,22 @@ func getBytesResult() [32]byte {
 }
 
 func Compute(input []byte) [32]byte {
-	initH()
+	var d digest
+	d.Reset()
+	d.Write(input)
 
-	padded := paddingMessage(input)
-	blocks := parsePaddedMessage(padded)
+	return d.checkSum()
+}
 
-	for i := 0; i < len(blocks); i++ {
-		schedule := prepareMessageSchedule(blocks[i])
-		compression(schedule)
-	}
+// Compute224 SHA224 differs from SHA256 only with initial hash value and truncated result
+func Compute224(input []byte) [28]byte {
+	var d = digest{is224: true}
+	d.Reset()
+	d.Write(input)
 
-	return getBytesResult()
+	var result [28]byte
+	sum := d.checkSum()
+	copy(result[:], sum[:])
+
+	return result
 }
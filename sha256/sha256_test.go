@@ -3,6 +3,9 @@ package sha256
 import (
 	"bytes"
 	"crypto/sha256"
+	"io"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -28,3 +31,139 @@ func TestVectors(t *testing.T) {
 		}
 	}
 }
+
+func TestStreaming(t *testing.T) {
+	for i, input := range inputs {
+		lib := sha256.Sum256([]byte(input))
+
+		for _, chunkSize := range []int{1, 3, 63, 64, 65, 1000} {
+			h := New()
+			data := []byte(input)
+
+			for len(data) > 0 {
+				n := chunkSize
+				if n > len(data) {
+					n = len(data)
+				}
+
+				h.Write(data[:n])
+				data = data[n:]
+			}
+
+			local := h.Sum(nil)
+			if bytes.Compare(local, lib[:]) != 0 {
+				t.Errorf("sha256.New: wrong result for `%d` with chunk size %d, local = `0x%s`, lib = `0x%s`", i, chunkSize, common.Bytes2Hex(local), common.Bytes2Hex(lib[:]))
+			}
+		}
+	}
+
+	h := New()
+	if _, err := io.Copy(h, strings.NewReader(inputs[len(inputs)-1])); err != nil {
+		t.Errorf("io.Copy: unexcpected error `%s`", err.Error())
+	}
+
+	// Sum must not change the state, so writing can be continued
+	h.Sum(nil)
+	h.Write([]byte(inputs[0]))
+
+	lib := sha256.Sum256([]byte(inputs[len(inputs)-1] + inputs[0]))
+	if local := h.Sum(nil); bytes.Compare(local, lib[:]) != 0 {
+		t.Errorf("sha256.New: wrong result after Sum, local = `0x%s`, lib = `0x%s`", common.Bytes2Hex(local), common.Bytes2Hex(lib[:]))
+	}
+
+	h.Reset()
+
+	lib = sha256.Sum256(nil)
+	if local := h.Sum(nil); bytes.Compare(local, lib[:]) != 0 {
+		t.Errorf("sha256.New: wrong result after Reset, local = `0x%s`, lib = `0x%s`", common.Bytes2Hex(local), common.Bytes2Hex(lib[:]))
+	}
+}
+
+// TestConcurrentCompute is meant to be run with `go test -race`
+func TestConcurrentCompute(t *testing.T) {
+	const (
+		workers    = 16
+		iterations = 250
+	)
+
+	expected := make([][32]byte, len(inputs))
+	for i, input := range inputs {
+		expected[i] = sha256.Sum256([]byte(input))
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func(w int) {
+			defer wg.Done()
+
+			for j := 0; j < iterations; j++ {
+				i := (w + j) % len(inputs)
+
+				if local := Compute([]byte(inputs[i])); local != expected[i] {
+					t.Errorf("sha256.Compute: wrong concurrent result for `%d`, local = `0x%s`, lib = `0x%s`", i, common.Bytes2Hex(local[:]), common.Bytes2Hex(expected[i][:]))
+					return
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+}
+
+type Vector224 struct {
+	m      string
+	digest string
+}
+
+// vectors224 from FIPS 180-4 examples
+var vectors224 = []Vector224{
+	{
+		m:      "",
+		digest: "d14a028c2a3a2bc9476102bb288234c415a2b01f828ea62ac5b3e42f",
+	},
+	{
+		m:      "abc",
+		digest: "23097d223405d8228642a477bda255b32aadbce4bda0b3f7e36c9da7",
+	},
+	{
+		m:      "abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq",
+		digest: "75388b16512776cc5dba5da1fd890150b0c6455cb4f58b1952522525",
+	},
+	{
+		m:      strings.Repeat("a", 1000000),
+		digest: "20794655980c91d8bbb4c1ea97618a4bf03f42581948b2ee4ee7ad67",
+	},
+}
+
+func TestVectors224(t *testing.T) {
+	for i, vector := range vectors224 {
+		expected := common.Hex2Bytes(vector.digest)
+
+		local := Compute224([]byte(vector.m))
+		if bytes.Compare(local[:], expected) != 0 {
+			t.Errorf("sha256.Compute224: wrong result for `%d`, local = `0x%s`, expected = `0x%s`", i, common.Bytes2Hex(local[:]), vector.digest)
+		}
+
+		h := New224()
+		io.Copy(h, strings.NewReader(vector.m))
+
+		if streamed := h.Sum(nil); bytes.Compare(streamed, expected) != 0 {
+			t.Errorf("sha256.New224: wrong result for `%d`, local = `0x%s`, expected = `0x%s`", i, common.Bytes2Hex(streamed), vector.digest)
+		}
+	}
+
+	for i, input := range inputs {
+		local := Compute224([]byte(input))
+		lib := sha256.Sum224([]byte(input))
+
+		if bytes.Compare(local[:], lib[:]) != 0 {
+			t.Errorf("sha256.Compute224: wrong result for `%d`, local = `0x%s`, lib = `0x%s`", i, common.Bytes2Hex(local[:]), common.Bytes2Hex(lib[:]))
+		}
+	}
+
+	if size := New224().Size(); size != Size224 {
+		t.Errorf("sha256.New224: wrong size %d", size)
+	}
+}
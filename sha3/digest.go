@@ -0,0 +1,188 @@
+package sha3
+
+import (
+	"encoding/binary"
+	"hash"
+	"io"
+)
+
+const (
+	Size224 = 28
+	Size256 = 32
+	Size384 = 48
+	Size512 = 64
+
+	// maxRate rate of SHAKE128, the biggest one
+	maxRate = 168
+)
+
+// domain separation bits appended to message before pad10*1
+const (
+	dsKeccak = 0x01
+	dsSHA3   = 0x06
+	dsShake  = 0x1f
+)
+
+// ShakeHash extendable output function, any amount of output can be read after writing,
+// Sum returns output of default size (twice the security strength)
+type ShakeHash interface {
+	hash.Hash
+	io.Reader
+}
+
+// digest is a sponge over Keccak-f[1600], rate bytes of state are absorbed and squeezed per permutation,
+// the rest 200 - rate bytes (capacity) are never touched directly
+type digest struct {
+	a    [25]uint64
+	buf  [maxRate]byte
+	rate int
+	ds   byte
+	size int
+	// filled amount of buffered input when absorbing, amount of already read output when squeezing
+	filled    int
+	squeezing bool
+}
+
+func newDigest(rate int, ds byte, size int) *digest {
+	return &digest{rate: rate, ds: ds, size: size}
+}
+
+// New224 returns hash.Hash that computes SHA3-224 checksum
+func New224() hash.Hash {
+	return newDigest(200-2*Size224, dsSHA3, Size224)
+}
+
+// New256 returns hash.Hash that computes SHA3-256 checksum
+func New256() hash.Hash {
+	return newDigest(200-2*Size256, dsSHA3, Size256)
+}
+
+// New384 returns hash.Hash that computes SHA3-384 checksum
+func New384() hash.Hash {
+	return newDigest(200-2*Size384, dsSHA3, Size384)
+}
+
+// New512 returns hash.Hash that computes SHA3-512 checksum
+func New512() hash.Hash {
+	return newDigest(200-2*Size512, dsSHA3, Size512)
+}
+
+// NewLegacyKeccak256 returns hash.Hash that computes Keccak-256 checksum used by Ethereum,
+// it is SHA3-256 with original Keccak padding
+func NewLegacyKeccak256() hash.Hash {
+	return newDigest(200-2*Size256, dsKeccak, Size256)
+}
+
+// NewShake128 returns SHAKE128 with 128-bit security, its Sum returns 32 bytes
+func NewShake128() ShakeHash {
+	return newDigest(maxRate, dsShake, 32)
+}
+
+// NewShake256 returns SHAKE256 with 256-bit security, its Sum returns 64 bytes
+func NewShake256() ShakeHash {
+	return newDigest(200-2*32, dsShake, 64)
+}
+
+func (d *digest) Reset() {
+	d.a = [25]uint64{}
+	d.filled = 0
+	d.squeezing = false
+}
+
+func (d *digest) Size() int {
+	return d.size
+}
+
+func (d *digest) BlockSize() int {
+	return d.rate
+}
+
+// Write absorbs p, writing after Read is not allowed
+func (d *digest) Write(p []byte) (int, error) {
+	if d.squeezing {
+		panic("sha3: Write after Read")
+	}
+
+	written := len(p)
+
+	for len(p) > 0 {
+		n := copy(d.buf[d.filled:d.rate], p)
+		d.filled += n
+		p = p[n:]
+
+		if d.filled == d.rate {
+			d.absorbBlock()
+		}
+	}
+
+	return written, nil
+}
+
+// Read squeezes next len(out) bytes of output, it never fails
+func (d *digest) Read(out []byte) (int, error) {
+	if !d.squeezing {
+		d.pad()
+	}
+
+	read := len(out)
+
+	for len(out) > 0 {
+		if d.filled == d.rate {
+			keccakF1600(&d.a)
+			d.squeezeBlock()
+		}
+
+		n := copy(out, d.buf[d.filled:d.rate])
+		d.filled += n
+		out = out[n:]
+	}
+
+	return read, nil
+}
+
+// Sum appends current hash to b, the state of d is not changed, so writing can be continued
+func (d *digest) Sum(b []byte) []byte {
+	if d.squeezing {
+		panic("sha3: Sum after Read")
+	}
+
+	// work with copy to keep d untouched
+	tmp := *d
+	result := make([]byte, d.size)
+	tmp.Read(result)
+
+	return append(b, result...)
+}
+
+// absorbBlock XORs full buffered block into the first rate bytes of state
+func (d *digest) absorbBlock() {
+	for i := 0; i < d.rate/8; i++ {
+		d.a[i] ^= binary.LittleEndian.Uint64(d.buf[8*i:])
+	}
+
+	keccakF1600(&d.a)
+	d.filled = 0
+}
+
+// pad domain bits and pad10*1, the first and the last padding bits can be in the same byte
+func (d *digest) pad() {
+	for i := d.filled; i < d.rate; i++ {
+		d.buf[i] = 0
+	}
+
+	d.buf[d.filled] ^= d.ds
+	d.buf[d.rate-1] ^= 0x80
+	d.absorbBlock()
+
+	d.squeezing = true
+	d.squeezeBlock()
+}
+
+// squeezeBlock copies the first rate bytes of state to buf
+func (d *digest) squeezeBlock() {
+	for i := 0; i < d.rate/8; i++ {
+		binary.LittleEndian.PutUint64(d.buf[8*i:], d.a[i])
+	}
+
+	d.filled = 0
+}
@@ -0,0 +1,64 @@
+package sha3
+
+import "hash"
+
+func compute(h hash.Hash, input []byte) []byte {
+	h.Write(input)
+
+	return h.Sum(nil)
+}
+
+func Compute224(input []byte) [28]byte {
+	var result [28]byte
+	copy(result[:], compute(New224(), input))
+
+	return result
+}
+
+func Compute256(input []byte) [32]byte {
+	var result [32]byte
+	copy(result[:], compute(New256(), input))
+
+	return result
+}
+
+func Compute384(input []byte) [48]byte {
+	var result [48]byte
+	copy(result[:], compute(New384(), input))
+
+	return result
+}
+
+func Compute512(input []byte) [64]byte {
+	var result [64]byte
+	copy(result[:], compute(New512(), input))
+
+	return result
+}
+
+// ComputeKeccak256 the same as crypto.Keccak256 from go-ethereum
+func ComputeKeccak256(input []byte) [32]byte {
+	var result [32]byte
+	copy(result[:], compute(NewLegacyKeccak256(), input))
+
+	return result
+}
+
+// ComputeShake128 first length bytes of SHAKE128 output
+func ComputeShake128(input []byte, length int) []byte {
+	return shake(NewShake128(), input, length)
+}
+
+// ComputeShake256 first length bytes of SHAKE256 output
+func ComputeShake256(input []byte, length int) []byte {
+	return shake(NewShake256(), input, length)
+}
+
+func shake(h ShakeHash, input []byte, length int) []byte {
+	h.Write(input)
+
+	result := make([]byte, length)
+	h.Read(result)
+
+	return result
+}
@@ -0,0 +1,199 @@
+package sha3
+
+import (
+	"bytes"
+	"hash"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type Vector struct {
+	m        string
+	sha3_224 string
+	sha3_256 string
+	sha3_384 string
+	sha3_512 string
+	shake128 string
+	shake256 string
+}
+
+// vectors from NIST examples, the last message is 1600 bits of 0xa3
+var vectors = []Vector{
+	{
+		m:        "",
+		sha3_224: "6b4e03423667dbb73b6e15454f0eb1abd4597f9a1b078e3f5b5a6bc7",
+		sha3_256: "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a",
+		sha3_384: "0c63a75b845e4f7d01107d852e4c2485c51a50aaaa94fc61995e71bbee983a2ac3713831264adb47fb6bd1e058d5f004",
+		sha3_512: "a69f73cca23a9ac5c8b567dc185a756e97c982164fe25859e0d1dcc1475c80a615b2123af1f5f94c11e3e9402c3ac558f500199d95b6d3e301758586281dcd26",
+		shake128: "7f9c2ba4e88f827d616045507605853ed73b8093f6efbc88eb1a6eacfa66ef26",
+		shake256: "46b9dd2b0ba88d13233b3feb743eeb243fcd52ea62b81b82b50c27646ed5762fd75dc4ddd8c0f200cb05019d67b592f6fc821c49479ab48640292eacb3b7c4be",
+	},
+	{
+		m:        "abc",
+		sha3_224: "e642824c3f8cf24ad09234ee7d3c766fc9a3a5168d0c94ad73b46fdf",
+		sha3_256: "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532",
+		sha3_384: "ec01498288516fc926459f58e2c6ad8df9b473cb0fc08c2596da7cf0e49be4b298d88cea927ac7f539f1edf228376d25",
+		sha3_512: "b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0",
+		shake128: "5881092dd818bf5cf8a3ddb793fbcba74097d5c526a6d35f97b83351940f2cc8",
+		shake256: "483366601360a8771c6863080cc4114d8db44530f8f1e1ee4f94ea37e78b5739d5a15bef186a5386c75744c0527e1faa9f8726e462a12a4feb06bd8801e751e4",
+	},
+	{
+		m:        strings.Repeat("\xa3", 200),
+		sha3_224: "9376816aba503f72f96ce7eb65ac095deee3be4bf9bbc2a1cb7e11e0",
+		sha3_256: "79f38adec5c20307a98ef76e8324afbfd46cfd81b22e3973c65fa1bd9de31787",
+		sha3_384: "1881de2ca7e41ef95dc4732b8f5f002b189cc1e42b74168ed1732649ce1dbcdd76197a31fd55ee989f2d7050dd473e8f",
+		sha3_512: "e76dfad22084a8b1467fcf2ffa58361bec7628edf5f3fdc0e4805dc48caeeca81b7c13c30adf52a3659584739a2df46be589c51ca1a4a8416df6545a1ce8ba00",
+		shake128: "131ab8d2b594946b9c81333f9bb6e0ce75c3b93104fa3469d3917457385da037",
+		shake256: "cd8a920ed141aa0407a22d59288652e9d9f1a7ee0c1e7c1ca699424da84a904d2d700caae7396ece96604440577da4f3aa22aeb8857f961c4cd8e06f0ae6610b",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	for i, vector := range vectors {
+		m := []byte(vector.m)
+
+		local224 := Compute224(m)
+		if common.Bytes2Hex(local224[:]) != vector.sha3_224 {
+			t.Errorf("sha3.Compute224: wrong result for `%d`, local = `0x%s`, expected = `0x%s`", i, common.Bytes2Hex(local224[:]), vector.sha3_224)
+		}
+
+		local256 := Compute256(m)
+		if common.Bytes2Hex(local256[:]) != vector.sha3_256 {
+			t.Errorf("sha3.Compute256: wrong result for `%d`, local = `0x%s`, expected = `0x%s`", i, common.Bytes2Hex(local256[:]), vector.sha3_256)
+		}
+
+		local384 := Compute384(m)
+		if common.Bytes2Hex(local384[:]) != vector.sha3_384 {
+			t.Errorf("sha3.Compute384: wrong result for `%d`, local = `0x%s`, expected = `0x%s`", i, common.Bytes2Hex(local384[:]), vector.sha3_384)
+		}
+
+		local512 := Compute512(m)
+		if common.Bytes2Hex(local512[:]) != vector.sha3_512 {
+			t.Errorf("sha3.Compute512: wrong result for `%d`, local = `0x%s`, expected = `0x%s`", i, common.Bytes2Hex(local512[:]), vector.sha3_512)
+		}
+
+		shake128 := ComputeShake128(m, 32)
+		if common.Bytes2Hex(shake128) != vector.shake128 {
+			t.Errorf("sha3.ComputeShake128: wrong result for `%d`, local = `0x%s`, expected = `0x%s`", i, common.Bytes2Hex(shake128), vector.shake128)
+		}
+
+		shake256 := ComputeShake256(m, 64)
+		if common.Bytes2Hex(shake256) != vector.shake256 {
+			t.Errorf("sha3.ComputeShake256: wrong result for `%d`, local = `0x%s`, expected = `0x%s`", i, common.Bytes2Hex(shake256), vector.shake256)
+		}
+	}
+}
+
+func TestKeccak256Lib(t *testing.T) {
+	empty := ComputeKeccak256(nil)
+	if common.Bytes2Hex(empty[:]) != "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470" {
+		t.Errorf("sha3.ComputeKeccak256: wrong hash of empty message")
+	}
+
+	// lengths around rate borders
+	data := make([]byte, 3*136+1)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	for length := 0; length <= len(data); length++ {
+		local := ComputeKeccak256(data[:length])
+		if bytes.Compare(local[:], crypto.Keccak256(data[:length])) != 0 {
+			t.Errorf("sha3.ComputeKeccak256: hash differs from go-ethereum for %d bytes", length)
+		}
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	constructors := map[string]func() hash.Hash{
+		"New224":             New224,
+		"New256":             New256,
+		"New384":             New384,
+		"New512":             New512,
+		"NewLegacyKeccak256": NewLegacyKeccak256,
+		"NewShake128":        func() hash.Hash { return NewShake128() },
+		"NewShake256":        func() hash.Hash { return NewShake256() },
+	}
+
+	input := []byte(strings.Repeat("Some example of loooooooooooooooooooonger message", 20))
+
+	for name, constructor := range constructors {
+		h := constructor()
+		expected := constructor()
+		expected.Write(input)
+
+		data := input
+		for len(data) > 0 {
+			n := 7
+			if n > len(data) {
+				n = len(data)
+			}
+
+			h.Write(data[:n])
+			data = data[n:]
+		}
+
+		sum := h.Sum(nil)
+		if bytes.Compare(sum, expected.Sum(nil)) != 0 || len(sum) != h.Size() {
+			t.Errorf("sha3.%s: wrong streaming result", name)
+		}
+
+		// Sum does not change state
+		h.Write([]byte("!"))
+		expected.Write([]byte("!"))
+		if bytes.Compare(h.Sum(nil), expected.Sum(nil)) != 0 {
+			t.Errorf("sha3.%s: wrong result after Sum", name)
+		}
+
+		h.Reset()
+		if bytes.Compare(h.Sum(nil), constructor().Sum(nil)) != 0 {
+			t.Errorf("sha3.%s: wrong result after Reset", name)
+		}
+	}
+}
+
+// TestShakeReader output read in small pieces must be the prefix of one long output
+func TestShakeReader(t *testing.T) {
+	for name, constructor := range map[string]func() ShakeHash{"NewShake128": NewShake128, "NewShake256": NewShake256} {
+		h := constructor()
+		h.Write([]byte("abc"))
+
+		// several rate blocks
+		expected := make([]byte, 1000)
+		h.Read(expected)
+
+		h.Reset()
+		h.Write([]byte("abc"))
+
+		result := make([]byte, 0, len(expected))
+		for len(result) < len(expected) {
+			piece := make([]byte, 13)
+			n, err := h.Read(piece)
+			if err != nil || n != len(piece) {
+				t.Fatalf("sha3.%s: unexcpected read result", name)
+			}
+
+			result = append(result, piece...)
+		}
+
+		if bytes.Compare(result[:len(expected)], expected) != 0 {
+			t.Errorf("sha3.%s: wrong result of streaming read", name)
+		}
+
+	}
+}
+
+func TestWriteAfterRead(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("sha3.Write: writing after reading is allowed")
+		}
+	}()
+
+	h := NewShake128()
+	h.Read(make([]byte, 1))
+	h.Write([]byte("abc"))
+}
@@ -0,0 +1,126 @@
+package sha512
+
+import (
+	"encoding/binary"
+	"hash"
+)
+
+const (
+	Size       = 64
+	Size384    = 48
+	Size512224 = 28
+	Size512256 = 32
+	BlockSize  = 128
+)
+
+// digest is a streaming state for all SHA512 family hashes, they differ only with initial hash value and result size
+type digest struct {
+	h      [8]uint64
+	block  [BlockSize]byte
+	filled int
+	length uint64
+	iv     [8]uint64
+	size   int
+}
+
+func newDigest(iv [8]uint64, size int) hash.Hash {
+	d := &digest{iv: iv, size: size}
+	d.Reset()
+	return d
+}
+
+// New returns hash.Hash that computes SHA512 checksum
+func New() hash.Hash {
+	return newDigest(initialH, Size)
+}
+
+// New384 returns hash.Hash that computes SHA384 checksum
+func New384() hash.Hash {
+	return newDigest(initialH384, Size384)
+}
+
+// New512_224 returns hash.Hash that computes SHA512/224 checksum
+func New512_224() hash.Hash {
+	return newDigest(initialH512_224, Size512224)
+}
+
+// New512_256 returns hash.Hash that computes SHA512/256 checksum
+func New512_256() hash.Hash {
+	return newDigest(initialH512_256, Size512256)
+}
+
+func (d *digest) Reset() {
+	d.h = d.iv
+	d.filled = 0
+	d.length = 0
+}
+
+func (d *digest) Size() int {
+	return d.size
+}
+
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	written := len(p)
+	d.length += uint64(written)
+
+	if d.filled > 0 {
+		n := copy(d.block[d.filled:], p)
+		d.filled += n
+		p = p[n:]
+
+		if d.filled < BlockSize {
+			return written, nil
+		}
+
+		d.processBlock(d.block[:])
+		d.filled = 0
+	}
+
+	for len(p) >= BlockSize {
+		d.processBlock(p[:BlockSize])
+		p = p[BlockSize:]
+	}
+
+	d.filled = copy(d.block[:], p)
+
+	return written, nil
+}
+
+// Sum appends current hash to b, the state of d is not changed, so writing can be continued
+func (d *digest) Sum(b []byte) []byte {
+	// work with copy to keep d untouched
+	tmp := *d
+	result := tmp.checkSum()
+
+	return append(b, result[:d.size]...)
+}
+
+func (d *digest) processBlock(block []byte) {
+	schedule := prepareMessageSchedule(block)
+	compression(&d.h, schedule)
+}
+
+func (d *digest) checkSum() [Size]byte {
+	messageLength := d.length
+
+	var padding [BlockSize + 16]byte
+	padding[0] = 0x80
+
+	zerosAmount := (128 + 111 - messageLength%128) % 128
+
+	// message length in bits is 128-bit big endian number
+	binary.BigEndian.PutUint64(padding[1+zerosAmount:], messageLength>>61)
+	binary.BigEndian.PutUint64(padding[1+zerosAmount+8:], messageLength<<3)
+
+	d.Write(padding[:1+zerosAmount+16])
+
+	if d.filled != 0 {
+		panic("padded message length is invalid")
+	}
+
+	return getBytesResult(d.h)
+}
@@ -0,0 +1,29 @@
+package sha512
+
+import (
+	"math/bits"
+)
+
+func SmallSigma1(x uint64) uint64 {
+	return bits.RotateLeft64(x, -19) ^ bits.RotateLeft64(x, -61) ^ (x >> 6)
+}
+
+func SmallSigma0(x uint64) uint64 {
+	return bits.RotateLeft64(x, -1) ^ bits.RotateLeft64(x, -8) ^ (x >> 7)
+}
+
+func BigSigma0(x uint64) uint64 {
+	return bits.RotateLeft64(x, -28) ^ bits.RotateLeft64(x, -34) ^ bits.RotateLeft64(x, -39)
+}
+
+func BigSigma1(x uint64) uint64 {
+	return bits.RotateLeft64(x, -14) ^ bits.RotateLeft64(x, -18) ^ bits.RotateLeft64(x, -41)
+}
+
+func Ch(x, y, z uint64) uint64 {
+	return (x & y) ^ (^x & z)
+}
+
+func Maj(x, y, z uint64) uint64 {
+	return (x & y) ^ (x & z) ^ (y & z)
+}
@@ -0,0 +1,132 @@
+package sha512
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"hash"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var inputs = []string{
+	"",
+	"abc",
+	"abcdefghbcdefghicdefghijdefghijkefghijklfghijklmghijklmnhijklmnoijklmnopjklmnopqklmnopqrlmnopqrsmnopqrstnopqrstu",
+	"Hello World!",
+	"sha512",
+	strings.Repeat("Some example of loooooooooooooooooooonger message", 20),
+	strings.Repeat("a", 111),
+	strings.Repeat("a", 112),
+	strings.Repeat("a", 128),
+}
+
+type Vector struct {
+	m      string
+	sha384 string
+	sha512 string
+}
+
+// vectors from FIPS 180-4 examples
+var vectors = []Vector{
+	{
+		m:      "abc",
+		sha384: "cb00753f45a35e8bb5a03d699ac65007272c32ab0eded1631a8b605a43ff5bed8086072ba1e7cc2358baeca134c825a7",
+		sha512: "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+	},
+	{
+		m:      "abcdefghbcdefghicdefghijdefghijkefghijklfghijklmghijklmnhijklmnoijklmnopjklmnopqklmnopqrlmnopqrsmnopqrstnopqrstu",
+		sha384: "09330c33f71147e83d192fc782cd1b4753111b173b3b05d22fa08086e3b0f712fcc7c71a557e2db966c3e9fa91746039",
+		sha512: "8e959b75dae313da8cf4f72814fc143f8f7779c6eb9f7fa17299aeadb6889018501d289e4900f7e4331b99dec4b5433ac7d329eeb6dd26545e96e55b874be909",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	for i, vector := range vectors {
+		local384 := Compute384([]byte(vector.m))
+		if bytes.Compare(local384[:], common.Hex2Bytes(vector.sha384)) != 0 {
+			t.Errorf("sha512.Compute384: wrong result for `%d`, local = `0x%s`, expected = `0x%s`", i, common.Bytes2Hex(local384[:]), vector.sha384)
+		}
+
+		local512 := Compute([]byte(vector.m))
+		if bytes.Compare(local512[:], common.Hex2Bytes(vector.sha512)) != 0 {
+			t.Errorf("sha512.Compute: wrong result for `%d`, local = `0x%s`, expected = `0x%s`", i, common.Bytes2Hex(local512[:]), vector.sha512)
+		}
+	}
+}
+
+func TestLib(t *testing.T) {
+	for i, input := range inputs {
+		data := []byte(input)
+
+		local512 := Compute(data)
+		lib512 := sha512.Sum512(data)
+		if bytes.Compare(local512[:], lib512[:]) != 0 {
+			t.Errorf("sha512.Compute: wrong result for `%d`, local = `0x%s`, lib = `0x%s`", i, common.Bytes2Hex(local512[:]), common.Bytes2Hex(lib512[:]))
+		}
+
+		local384 := Compute384(data)
+		lib384 := sha512.Sum384(data)
+		if bytes.Compare(local384[:], lib384[:]) != 0 {
+			t.Errorf("sha512.Compute384: wrong result for `%d`, local = `0x%s`, lib = `0x%s`", i, common.Bytes2Hex(local384[:]), common.Bytes2Hex(lib384[:]))
+		}
+
+		local224 := Compute512_224(data)
+		lib224 := sha512.Sum512_224(data)
+		if bytes.Compare(local224[:], lib224[:]) != 0 {
+			t.Errorf("sha512.Compute512_224: wrong result for `%d`, local = `0x%s`, lib = `0x%s`", i, common.Bytes2Hex(local224[:]), common.Bytes2Hex(lib224[:]))
+		}
+
+		local256 := Compute512_256(data)
+		lib256 := sha512.Sum512_256(data)
+		if bytes.Compare(local256[:], lib256[:]) != 0 {
+			t.Errorf("sha512.Compute512_256: wrong result for `%d`, local = `0x%s`, lib = `0x%s`", i, common.Bytes2Hex(local256[:]), common.Bytes2Hex(lib256[:]))
+		}
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	constructors := map[string][2]func() hash.Hash{
+		"New":        {New, sha512.New},
+		"New384":     {New384, sha512.New384},
+		"New512_224": {New512_224, sha512.New512_224},
+		"New512_256": {New512_256, sha512.New512_256},
+	}
+
+	for name, constructor := range constructors {
+		for i, input := range inputs {
+			local := constructor[0]()
+			lib := constructor[1]()
+
+			if local.Size() != lib.Size() || local.BlockSize() != lib.BlockSize() {
+				t.Errorf("sha512.%s: wrong sizes", name)
+			}
+
+			io.Copy(lib, strings.NewReader(input))
+
+			data := []byte(input)
+			for len(data) > 0 {
+				n := 7
+				if n > len(data) {
+					n = len(data)
+				}
+
+				local.Write(data[:n])
+				data = data[n:]
+			}
+
+			if bytes.Compare(local.Sum(nil), lib.Sum(nil)) != 0 {
+				t.Errorf("sha512.%s: wrong result for `%d`, local = `0x%s`, lib = `0x%s`", name, i, common.Bytes2Hex(local.Sum(nil)), common.Bytes2Hex(lib.Sum(nil)))
+			}
+
+			local.Reset()
+			lib.Reset()
+
+			if bytes.Compare(local.Sum(nil), lib.Sum(nil)) != 0 {
+				t.Errorf("sha512.%s: wrong result after Reset, local = `0x%s`, lib = `0x%s`", name, common.Bytes2Hex(local.Sum(nil)), common.Bytes2Hex(lib.Sum(nil)))
+			}
+		}
+	}
+}